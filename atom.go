@@ -0,0 +1,150 @@
+package movabletype
+
+import (
+	"encoding/xml"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// updated of entries and feeds without DATE, so that the output is the same
+// on every run
+var atomEpoch = time.Unix(0, 0).UTC()
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Link    atomLink    `xml:"link"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	XMLName    xml.Name       `xml:"entry"`
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Author     *atomAuthor    `xml:"author"`
+	Summary    string         `xml:"summary"`
+	Content    atomContent    `xml:"content"`
+	Categories []atomCategory `xml:"category"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// ToAtomEntry returns e as an <entry> element of Atom 1.0.
+//
+// id is UNIQUE URL, or a URN made from BASENAME such as
+// urn:movabletype:2017/04/09/194939 when UNIQUE URL is empty. Without
+// BASENAME, DATE is used such as urn:movabletype:entry-20170409194939, and
+// without either the id is urn:movabletype:entry-1.
+// updated is DATE, or the Unix epoch without DATE. summary is
+// ExcerptOrSummary cut to 200 runes and content is FullBody as HTML.
+func (e *Entry) ToAtomEntry() string {
+	b, _ := xml.MarshalIndent(newAtomEntry(e, atomID(e, "", 1), atomEpoch), "", "\t")
+	return string(b)
+}
+
+// EntriesToAtom returns an Atom 1.0 feed of entries with feedID, feedTitle
+// and a link to feedURL. updated of the feed is the latest DATE of entries,
+// or the Unix epoch if no entry has DATE, so that the same entries always
+// render the same feed. Entries without DATE have the updated of the feed.
+//
+// ids of entries are UNIQUE URL, or made from feedID and BASENAME such as
+// https://example.com/blog/poem for the feedID https://example.com/blog so
+// that they do not collide with entries of other feeds. Entries without
+// BASENAME use DATE or their position such as entry-3 instead.
+func EntriesToAtom(entries []*Entry, feedID, feedTitle, feedURL string) (string, error) {
+	feed := atomFeed{
+		ID:    feedID,
+		Title: feedTitle,
+		Link:  atomLink{Href: feedURL, Rel: "alternate"},
+	}
+
+	var updated time.Time
+	for _, e := range entries {
+		if e.Date.After(updated) {
+			updated = e.Date
+		}
+	}
+	if updated.IsZero() {
+		updated = atomEpoch
+	}
+	feed.Updated = updated.Format(time.RFC3339)
+
+	for i, e := range entries {
+		feed.Entries = append(feed.Entries, newAtomEntry(e, atomID(e, feedID, i+1), updated))
+	}
+
+	b, err := xml.MarshalIndent(feed, "", "\t")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(b) + "\n", nil
+}
+
+// newAtomEntry returns the Atom entry of e with id. updated is used when e
+// has no DATE.
+func newAtomEntry(e *Entry, id string, updated time.Time) atomEntry {
+	if !e.Date.IsZero() {
+		updated = e.Date
+	}
+
+	entry := atomEntry{
+		ID:      id,
+		Title:   e.Title,
+		Updated: updated.Format(time.RFC3339),
+		Summary: e.ExcerptOrSummary(feedSummaryLength),
+		Content: atomContent{Type: "html", Body: e.FullBody()},
+	}
+
+	if e.Author != "" {
+		entry.Author = &atomAuthor{Name: e.Author}
+	}
+	for _, c := range e.AllCategories() {
+		entry.Categories = append(entry.Categories, atomCategory{Term: c})
+	}
+
+	return entry
+}
+
+// atomID returns the id of the n-th entry e of the feed feedID. Without
+// feedID, the id is a URN of urn:movabletype.
+func atomID(e *Entry, feedID string, n int) string {
+	if e.UniqueURL != "" {
+		return e.UniqueURL
+	}
+
+	key := e.Basename
+	if key == "" && !e.Date.IsZero() {
+		key = "entry-" + e.Date.UTC().Format("20060102150405")
+	}
+	if key == "" {
+		key = "entry-" + strconv.Itoa(n)
+	}
+	key = (&url.URL{Path: strings.TrimLeft(key, "/")}).EscapedPath()
+
+	if feedID == "" {
+		return "urn:movabletype:" + key
+	}
+	return strings.TrimRight(feedID, "/") + "/" + key
+}
@@ -0,0 +1,138 @@
+package movabletype_test
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestToAtomEntry(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `<entry>
+	<id>urn:movabletype:poem</id>
+	<title>ポエム</title>
+	<updated>2017-04-22T20:41:58Z</updated>
+	<author>
+		<name>catatsuy</name>
+	</author>
+	<summary>body</summary>
+	<content type="html">&lt;p&gt;body&lt;/p&gt;&#xA;&#xA;&lt;p&gt;extended body&lt;/p&gt;&#xA;</content>
+	<category term="ブログ"></category>
+	<category term="ポエム"></category>
+	<category term="技術系"></category>
+</entry>`
+	if got := mts[0].ToAtomEntry(); got != expected {
+		t.Errorf("ToAtomEntry expected\n%s\ngot\n%s", expected, got)
+	}
+
+	if got := mts[1].ToAtomEntry(); !strings.Contains(got, "<id>urn:movabletype:2017/04/09/194939</id>") {
+		t.Errorf("ToAtomEntry should have an id from BASENAME, got\n%s", got)
+	}
+
+	e := NewEntry()
+	e.UniqueURL = "https://example.typepad.com/blog/2014/05/post.html"
+	if got := e.ToAtomEntry(); !strings.Contains(got, "<id>https://example.typepad.com/blog/2014/05/post.html</id>") || strings.Contains(got, "<author>") {
+		t.Errorf("ToAtomEntry should use UNIQUE URL without author, got\n%s", got)
+	}
+}
+
+func TestEntriesToAtom(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	output, err := EntriesToAtom(mts, "urn:example:blog", "catatsuy's blog", "https://example.com/")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	d := xml.NewDecoder(strings.NewReader(output))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Atom should be well-formed, got error %q\n%s", err, output)
+		}
+	}
+
+	expected := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<id>urn:example:blog</id>`,
+		`<title>catatsuy&#39;s blog</title>`,
+		`<link href="https://example.com/" rel="alternate"></link>`,
+		// the later of the two entries
+		"\t<updated>2017-04-22T20:41:58Z</updated>\n\t<entry>",
+		`<id>urn:example:blog/poem</id>`,
+		`<id>urn:example:blog/2017/04/09/194939</id>`,
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("Atom should contain %q, got\n%s", s, output)
+		}
+	}
+
+	if strings.Count(output, "<entry>") != len(mts) {
+		t.Errorf("Atom should have %d entries, got\n%s", len(mts), output)
+	}
+}
+
+func TestEntriesToAtomWithoutBasenameAndDate(t *testing.T) {
+	dated := NewEntry()
+	dated.Title = "dated"
+	dated.Date = time.Date(2017, time.April, 22, 20, 41, 58, 0, time.FixedZone("JST", 9*60*60))
+	undated := NewEntry()
+	undated.Title = "undated"
+
+	output, err := EntriesToAtom([]*Entry{dated, undated}, "https://example.com/blog/", "blog", "https://example.com/blog/")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := []string{
+		`<id>https://example.com/blog/entry-20170422114158</id>`,
+		`<id>https://example.com/blog/entry-2</id>`,
+	}
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("Atom should contain %q, got\n%s", s, output)
+		}
+	}
+
+	// The entry without DATE has the updated of the feed
+	if strings.Count(output, "<updated>2017-04-22T20:41:58+09:00</updated>") != 3 {
+		t.Errorf("Atom should have updated of the feed and entries, got\n%s", output)
+	}
+
+	output, err = EntriesToAtom([]*Entry{undated}, "urn:example:blog", "blog", "https://example.com/")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if strings.Count(output, "<updated>1970-01-01T00:00:00Z</updated>") != 2 {
+		t.Errorf("Atom without DATE should have updated of the Unix epoch, got\n%s", output)
+	}
+
+	again, err := EntriesToAtom([]*Entry{undated}, "urn:example:blog", "blog", "https://example.com/")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if again != output {
+		t.Errorf("Atom of the same entries should be the same, got\n%s\nand\n%s", output, again)
+	}
+
+	if got := undated.ToAtomEntry(); !strings.Contains(got, "<id>urn:movabletype:entry-1</id>") || !strings.Contains(got, "<updated>1970-01-01T00:00:00Z</updated>") {
+		t.Errorf("ToAtomEntry should have id and updated of the Unix epoch, got\n%s", got)
+	}
+}
@@ -0,0 +1,50 @@
+package movabletype
+
+import "strings"
+
+// CategoryNode is a category in a tree built by BuildCategoryTree.
+type CategoryNode struct {
+	Name     string
+	Children []*CategoryNode
+}
+
+// child returns the child named name, adding it if it does not exist.
+func (n *CategoryNode) child(name string) *CategoryNode {
+	for _, c := range n.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	c := &CategoryNode{Name: name}
+	n.Children = append(n.Children, c)
+	return c
+}
+
+// BuildCategoryTree builds a tree of PRIMARY CATEGORY and CATEGORY of
+// entries. Each category is split on sep into nested categories, such as
+// Tech/Go into Go under Tech. Nodes are in order of first appearance and
+// empty names are skipped. An empty sep does not split categories.
+func BuildCategoryTree(entries []*Entry, sep string) []*CategoryNode {
+	root := &CategoryNode{}
+
+	for _, e := range entries {
+		for _, c := range e.AllCategories() {
+			names := []string{c}
+			if sep != "" {
+				names = strings.Split(c, sep)
+			}
+
+			n := root
+			for _, name := range names {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				n = n.child(name)
+			}
+		}
+	}
+
+	return root.Children
+}
@@ -0,0 +1,52 @@
+package movabletype_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	e1 := NewEntry()
+	e1.PrimaryCategory = "Tech/Go"
+	e1.Category = []string{"Tech/Go", "Diary"}
+
+	e2 := NewEntry()
+	e2.Category = []string{"Tech/Rust", "Tech / Go / Generics", "Tech//"}
+
+	expected := []*CategoryNode{
+		{Name: "Tech", Children: []*CategoryNode{
+			{Name: "Go", Children: []*CategoryNode{
+				{Name: "Generics"},
+			}},
+			{Name: "Rust"},
+		}},
+		{Name: "Diary"},
+	}
+
+	got := BuildCategoryTree([]*Entry{e1, e2}, "/")
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("BuildCategoryTree got %s; want %s", dumpCategoryTree(got), dumpCategoryTree(expected))
+	}
+
+	got = BuildCategoryTree([]*Entry{e1}, "")
+	expected = []*CategoryNode{{Name: "Tech/Go"}, {Name: "Diary"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("BuildCategoryTree without sep got %s; want %s", dumpCategoryTree(got), dumpCategoryTree(expected))
+	}
+}
+
+func dumpCategoryTree(nodes []*CategoryNode) string {
+	s := "["
+	for i, n := range nodes {
+		if i > 0 {
+			s += " "
+		}
+		s += n.Name
+		if len(n.Children) > 0 {
+			s += dumpCategoryTree(n.Children)
+		}
+	}
+	return s + "]"
+}
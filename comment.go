@@ -0,0 +1,141 @@
+package movabletype
+
+import (
+	"strings"
+	"time"
+)
+
+// Comment is a COMMENT block of Entry
+type Comment struct {
+	Author string    `json:"author,omitempty"`
+	Email  string    `json:"email,omitempty"`
+	URL    string    `json:"url,omitempty"`
+	IP     string    `json:"ip,omitempty"`
+	Date   time.Time `json:"date"`
+
+	Body string `json:"body,omitempty"`
+
+	// Raw text of the COMMENT block
+	Raw string `json:"raw,omitempty"`
+}
+
+// CommentEntry is an alias of Comment.
+type CommentEntry = Comment
+
+// ParseComments creates comments from COMMENT blocks in the input format,
+// such as Entry.Comment read with RawComment. The text of a single block
+// without the "COMMENT:" line and the "-----" separator is accepted as
+// well. DATE is interpreted in UTC.
+func ParseComments(raw string) ([]CommentEntry, error) {
+	cs := []CommentEntry{}
+
+	for _, block := range splitBlocks(raw, "COMMENT:") {
+		c, err := parseComment(block, time.UTC, nil)
+		if err != nil {
+			return nil, err
+		}
+		cs = append(cs, c)
+	}
+
+	return cs, nil
+}
+
+// parseComment creates Comment from the text of a COMMENT block.
+// DATE is interpreted in loc, also trying layouts after the built-in ones.
+// The block starts with AUTHOR, EMAIL, URL, IP and DATE lines followed
+// by the body of the comment.
+func parseComment(raw string, loc *time.Location, layouts []string) (Comment, error) {
+	c := Comment{Raw: raw}
+
+	lines := strings.SplitAfter(raw, "\n")
+
+	i := 0
+	for ; i < len(lines); i++ {
+		key, value, ok := splitBlockHeader(lines[i], "AUTHOR", "EMAIL", "URL", "IP", "DATE")
+		if !ok {
+			break
+		}
+
+		switch key {
+		case "AUTHOR":
+			c.Author = value
+		case "EMAIL":
+			c.Email = value
+		case "URL":
+			c.URL = value
+		case "IP":
+			c.IP = value
+		case "DATE":
+			if value == "" {
+				break
+			}
+			var err error
+			c.Date, err = parseDate(value, loc, layouts)
+			if err != nil {
+				return c, &ParseError{Field: "COMMENT", Value: value, Reason: "Parsing error on DATE column of COMMENT", Err: err}
+			}
+		}
+	}
+
+	c.Body = strings.Join(lines[i:], "")
+
+	return c, nil
+}
+
+// splitBlocks splits raw into the texts of the blocks started by header,
+// such as "COMMENT:", and ended by the "-----" separator.
+func splitBlocks(raw, header string) []string {
+	var blocks []string
+	block := ""
+
+	for _, line := range strings.SplitAfter(raw, "\n") {
+		switch strings.TrimRight(line, "\r\n") {
+		case header:
+			if block != "" {
+				blocks = append(blocks, block)
+			}
+			block = ""
+			continue
+		case "-----":
+			blocks = append(blocks, block)
+			block = ""
+			continue
+		}
+
+		block += line
+	}
+	if block != "" {
+		blocks = append(blocks, block)
+	}
+
+	return blocks
+}
+
+// splitBlockHeader splits a header line of a COMMENT or PING block.
+// ok is false if line is not a header line of one of keys.
+func splitBlockHeader(line string, keys ...string) (key, value string, ok bool) {
+	line = strings.TrimSuffix(line, "\n")
+
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", false
+	}
+
+	key = line[:i]
+	for _, k := range keys {
+		if key == k {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return "", "", false
+	}
+
+	value = line[i+1:]
+	if value != "" && !strings.HasPrefix(value, " ") {
+		return "", "", false
+	}
+
+	return key, strings.TrimPrefix(value, " "), true
+}
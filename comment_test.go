@@ -0,0 +1,149 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestParseComment(t *testing.T) {
+	buf := bytes.NewBufferString(`TITLE: title
+-----
+BODY:
+body
+-----
+COMMENT:
+AUTHOR: Foo
+EMAIL: foo@example.com
+IP: 127.0.0.1
+URL: http://www.example.com/
+DATE: 01/31/2002 03:47:06 PM
+This is
+the body of this comment.
+-----
+COMMENT:
+AUTHOR: Bar
+EMAIL:
+URL: 
+second comment
+-----
+--------
+`)
+
+	expected := []Comment{
+		{
+			Author: "Foo",
+			Email:  "foo@example.com",
+			URL:    "http://www.example.com/",
+			IP:     "127.0.0.1",
+			Date:   time.Date(2002, time.January, 31, 15, 47, 6, 0, time.UTC),
+			Body:   "This is\nthe body of this comment.\n",
+			Raw:    "AUTHOR: Foo\nEMAIL: foo@example.com\nIP: 127.0.0.1\nURL: http://www.example.com/\nDATE: 01/31/2002 03:47:06 PM\nThis is\nthe body of this comment.\n",
+		},
+		{
+			Author: "Bar",
+			Body:   "second comment\n",
+			Raw:    "AUTHOR: Bar\nEMAIL:\nURL: \nsecond comment\n",
+		},
+	}
+
+	mts, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(mts[0].Comments, expected) {
+		t.Errorf("Error parsing, expected %v; got %v", expected, mts[0].Comments)
+	}
+}
+
+func TestParseCommentInvalidDate(t *testing.T) {
+	buf := bytes.NewBufferString("COMMENT:\nAUTHOR: Foo\nDATE: yesterday\nbody\n-----\n--------\n")
+
+	_, err := Parse(buf)
+	if err == nil {
+		t.Errorf("Invalid DATE of COMMENT should be an error")
+	}
+}
+
+func TestParseRawComment(t *testing.T) {
+	input := `TITLE: title
+-----
+COMMENT:
+AUTHOR: Foo
+DATE: 01/31/2002 03:47:06 PM
+first comment
+-----
+COMMENT:
+AUTHOR: Bar
+DATE: yesterday
+second comment
+-----
+--------
+`
+
+	mts, err := ParseWithOptions(bytes.NewBufferString(input), ParseOptions{RawComment: true})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "COMMENT:\nAUTHOR: Foo\nDATE: 01/31/2002 03:47:06 PM\nfirst comment\n-----\nCOMMENT:\nAUTHOR: Bar\nDATE: yesterday\nsecond comment\n-----\n"
+	if mts[0].Comment != expected {
+		t.Errorf("m.Comment got %q; want %q", mts[0].Comment, expected)
+	}
+
+	if mts[0].Comments != nil {
+		t.Errorf("m.Comments should not be parsed, got %v", mts[0].Comments)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+
+	_, err = ParseComments(mts[0].Comment)
+	if !IsParseError(err) {
+		t.Errorf("got error %v; want ParseError of DATE", err)
+	}
+}
+
+func TestParseComments(t *testing.T) {
+	var featuretests = []struct {
+		raw      string
+		expected []CommentEntry
+	}{
+		{
+			"COMMENT:\nAUTHOR: Foo\nfirst comment\n-----\nCOMMENT:\nAUTHOR: Bar\nDATE: 01/31/2002 03:47:06 PM\nsecond comment\n-----\n",
+			[]CommentEntry{
+				{Author: "Foo", Body: "first comment\n", Raw: "AUTHOR: Foo\nfirst comment\n"},
+				{Author: "Bar", Date: time.Date(2002, time.January, 31, 15, 47, 6, 0, time.UTC), Body: "second comment\n", Raw: "AUTHOR: Bar\nDATE: 01/31/2002 03:47:06 PM\nsecond comment\n"},
+			},
+		},
+		{
+			"AUTHOR: Foo\nsingle comment\n",
+			[]CommentEntry{
+				{Author: "Foo", Body: "single comment\n", Raw: "AUTHOR: Foo\nsingle comment\n"},
+			},
+		},
+		{"", []CommentEntry{}},
+	}
+
+	for _, ft := range featuretests {
+		cs, err := ParseComments(ft.raw)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if !reflect.DeepEqual(cs, ft.expected) {
+			t.Errorf("ParseComments(%q) got %v; want %v", ft.raw, cs, ft.expected)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package movabletype
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// magic bytes at the beginning of gzip streams
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// decompressInput returns r decompressed when it is a gzip stream.
+// Other input is returned as is without consuming any bytes.
+func decompressInput(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+
+	// The error is returned again by the following Read
+	magic, _ := br.Peek(len(gzipMagic))
+	if !bytes.Equal(magic, gzipMagic) {
+		return br, nil
+	}
+
+	zr, err := gzip.NewReader(br)
+	if err != nil {
+		return br, errors.Wrap(err, "Failed to read gzip header")
+	}
+
+	return zr, nil
+}
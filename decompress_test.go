@@ -0,0 +1,47 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestParseWithAutoDecompress(t *testing.T) {
+	expected, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	plain, err := ParseString(sampleExport, WithAutoDecompress())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(plain, expected) {
+		t.Errorf("Plain input should parse as is, expected %v; got %v", expected, plain)
+	}
+
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	zw.Write([]byte(sampleExport))
+	zw.Close()
+
+	gzipped, err := Parse(buf, WithAutoDecompress())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(gzipped, expected) {
+		t.Errorf("Gzipped input should parse like plain input, expected %v; got %v", expected, gzipped)
+	}
+}
+
+func TestParseWithAutoDecompressBrokenHeader(t *testing.T) {
+	_, err := ParseBytes([]byte{0x1f, 0x8b, 0x00}, WithAutoDecompress())
+	if err == nil {
+		t.Errorf("Broken gzip header should be an error")
+	}
+}
@@ -0,0 +1,102 @@
+package movabletype
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ParseFromDir parses all .txt files in dir, such as exports of one file
+// per entry, and returns their entries sorted by DATE in ascending order.
+// Subdirectories are not read.
+//
+// When a file fails, the entries parsed so far are returned with the error
+// wrapped with the file name.
+func ParseFromDir(dir string) ([]*Entry, error) {
+	paths, err := exportFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	mts := []*Entry{}
+	for _, path := range paths {
+		entries, err := ParseFile(path)
+		mts = append(mts, entries...)
+		if err != nil {
+			return mts, err
+		}
+	}
+
+	SortByDate(mts, true)
+
+	return mts, nil
+}
+
+// WalkEntries calls fn for each entry of the .txt files in dir, reading
+// the files one by one in order of file name and entries in order of
+// appearance. Unlike ParseFromDir, entries are not sorted and only one
+// entry is held at a time.
+//
+// It stops at the first error, either of parsing, which is wrapped with
+// the file name, or returned by fn as is.
+func WalkEntries(dir string, fn func(*Entry) error) error {
+	paths, err := exportFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		err := walkFile(path, fn)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func walkFile(path string, fn func(*Entry) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to open %s", path)
+	}
+	defer f.Close()
+
+	p := newParser(f, ParseOptions{})
+	for {
+		m, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "Failed to parse %s", path)
+		}
+
+		err = fn(m)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// exportFiles returns the paths of the .txt files in dir in order of file
+// name.
+func exportFiles(dir string) ([]string, error) {
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to read %s", dir)
+	}
+
+	var paths []string
+	for _, de := range des {
+		if de.IsDir() || !strings.EqualFold(filepath.Ext(de.Name()), ".txt") {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, de.Name()))
+	}
+
+	return paths, nil
+}
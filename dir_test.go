@@ -0,0 +1,98 @@
+package movabletype_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func writeExportDir(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestParseFromDir(t *testing.T) {
+	dir := writeExportDir(t, map[string]string{
+		"a.txt":     "TITLE: third\nDATE: 04/22/2017 20:41:58\n-----\n--------\nTITLE: first\nDATE: 04/09/2017 19:49:39\n-----\n--------\n",
+		"b.TXT":     "TITLE: second\nDATE: 04/10/2017 00:00:00\n-----\n--------\n",
+		"notes.md":  "TITLE: ignored\n-----\n--------\n",
+		"empty.txt": "",
+	})
+	err := os.Mkdir(filepath.Join(dir, "sub.txt"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mts, err := ParseFromDir(dir)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := []string{"first", "second", "third"}
+	if got := titles(mts); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ParseFromDir got %q; want %q", got, expected)
+	}
+}
+
+func TestParseFromDirError(t *testing.T) {
+	dir := writeExportDir(t, map[string]string{
+		"a.txt":       "TITLE: valid\n-----\n--------\n",
+		"invalid.txt": "STATUS: Published\n-----\n--------\n",
+	})
+
+	mts, err := ParseFromDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "invalid.txt") {
+		t.Errorf("error should contain the file name, got %v", err)
+	}
+	if len(mts) != 1 || mts[0].Title != "valid" {
+		t.Errorf("entries before the error got %v", mts)
+	}
+
+	_, err = ParseFromDir(filepath.Join(dir, "missing"))
+	if err == nil {
+		t.Errorf("should return an error for a missing directory")
+	}
+}
+
+func TestWalkEntries(t *testing.T) {
+	dir := writeExportDir(t, map[string]string{
+		"a.txt": "TITLE: 1\n-----\n--------\nTITLE: 2\n-----\n--------\n",
+		"b.txt": "TITLE: 3\n-----\n--------\n",
+	})
+
+	var got []string
+	err := WalkEntries(dir, func(e *Entry) error {
+		got = append(got, e.Title)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if expected := []string{"1", "2", "3"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("WalkEntries got %q; want %q", got, expected)
+	}
+
+	errStop := errors.New("stop")
+	got = nil
+	err = WalkEntries(dir, func(e *Entry) error {
+		got = append(got, e.Title)
+		return errStop
+	})
+	if err != errStop {
+		t.Errorf("WalkEntries should return the error of fn, got %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("WalkEntries should stop at the error, got %q", got)
+	}
+}
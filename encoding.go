@@ -0,0 +1,256 @@
+package movabletype
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Names of the encodings reported by DetectEncoding
+const (
+	EncodingUTF8     = "utf-8"
+	EncodingShiftJIS = "shift_jis"
+	EncodingEUCJP    = "euc-jp"
+	EncodingUTF16LE  = "utf-16le"
+	EncodingUTF16BE  = "utf-16be"
+)
+
+// Byte order marks detected by DetectEncoding, with the encodings which
+// remove them while decoding
+var boms = []struct {
+	bom  []byte
+	name string
+	enc  encoding.Encoding
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, EncodingUTF8, unicode.UTF8BOM},
+	{[]byte{0xFF, 0xFE}, EncodingUTF16LE, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)},
+	{[]byte{0xFE, 0xFF}, EncodingUTF16BE, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM)},
+}
+
+// Charset to detect the encoding of the input with DetectEncoding
+const CharsetAuto = "auto"
+
+// size of the beginning of the input examined by CharsetAuto
+const detectSize = 64 * 1024
+
+// decodeInput returns r converted to UTF-8 according to the Encoding and
+// Charset of opts.
+func decodeInput(r io.Reader, opts ParseOptions) (io.Reader, error) {
+	enc := opts.Encoding
+
+	if enc == nil && strings.EqualFold(opts.Charset, CharsetAuto) {
+		br := bufio.NewReaderSize(r, detectSize)
+		// The error is returned again by the following Read
+		sample, _ := br.Peek(detectSize)
+
+		name, detected := detectEncoding(sample)
+		if opts.OnEncodingDetected != nil {
+			opts.OnEncodingDetected(name)
+		}
+		if name == EncodingUTF8 {
+			// UTF-8 BOM is removed by the Parser
+			return br, nil
+		}
+
+		r = br
+		enc = detected
+	} else if enc == nil && opts.Charset != "" {
+		var err error
+		enc, err = lookupEncoding(opts.Charset)
+		if err != nil {
+			return r, err
+		}
+	}
+
+	if enc == nil {
+		return r, nil
+	}
+
+	return newDecodeReader(r, enc), nil
+}
+
+// DetectEncoding guesses the encoding of b, the beginning of an export.
+// b starting with the BOM of UTF-8, UTF-16LE or UTF-16BE is EncodingUTF8,
+// EncodingUTF16LE or EncodingUTF16BE. Otherwise the encoding is guessed
+// from the byte patterns of b as EncodingUTF8, EncodingShiftJIS or
+// EncodingEUCJP. Bytes valid in both Shift_JIS and EUC-JP are taken as
+// EUC-JP when they read as half-width katakana in Shift_JIS, which is rare
+// in real text. Otherwise, or when b is valid in neither, it returns
+// EncodingUTF8.
+//
+// A multi-byte character cut at the end of b is ignored.
+func DetectEncoding(b []byte) string {
+	name, _ := detectEncoding(b)
+	return name
+}
+
+// DetectEncodingOf is DetectEncoding returning encoding.Encoding, which can
+// be passed to ParseWithEncoding. The encodings detected by BOM remove the
+// BOM while decoding.
+func DetectEncodingOf(b []byte) encoding.Encoding {
+	_, enc := detectEncoding(b)
+	return enc
+}
+
+// detectEncoding returns the name and the encoding of b detected by
+// DetectEncoding.
+func detectEncoding(b []byte) (string, encoding.Encoding) {
+	for _, bom := range boms {
+		if bytes.HasPrefix(b, bom.bom) {
+			return bom.name, bom.enc
+		}
+	}
+
+	name := guessEncoding(b)
+	enc, _ := lookupEncoding(name)
+
+	return name, enc
+}
+
+// guessEncoding guesses the encoding of b from its byte patterns.
+func guessEncoding(b []byte) string {
+	if validUTF8(b) {
+		return EncodingUTF8
+	}
+
+	sjis, kana := validShiftJIS(b)
+	euc := validEUCJP(b)
+	switch {
+	case sjis && !euc:
+		return EncodingShiftJIS
+	case euc && (!sjis || kana > 0):
+		return EncodingEUCJP
+	}
+
+	return EncodingUTF8
+}
+
+func validUTF8(b []byte) bool {
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 {
+			// a character cut at the end
+			return !utf8.FullRune(b)
+		}
+		b = b[size:]
+	}
+
+	return true
+}
+
+// validShiftJIS reports whether b is valid in Shift_JIS and returns the
+// number of half-width katakana in it.
+func validShiftJIS(b []byte) (bool, int) {
+	kana := 0
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c < 0x80:
+		case 0xA1 <= c && c <= 0xDF:
+			kana++
+		case 0x81 <= c && c <= 0x9F, 0xE0 <= c && c <= 0xFC:
+			i++
+			if i == len(b) {
+				return true, kana
+			}
+			t := b[i]
+			if t < 0x40 || t == 0x7F || t > 0xFC {
+				return false, kana
+			}
+		default:
+			return false, kana
+		}
+	}
+
+	return true, kana
+}
+
+func validEUCJP(b []byte) bool {
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		n := 0
+		switch {
+		case c < 0x80:
+		case c == 0x8E:
+			// half-width katakana
+			n = 1
+		case c == 0x8F:
+			// JIS X 0212
+			n = 2
+		case 0xA1 <= c && c <= 0xFE:
+			n = 1
+		default:
+			return false
+		}
+
+		for ; n > 0; n-- {
+			i++
+			if i == len(b) {
+				return true
+			}
+			if b[i] < 0xA1 || b[i] > 0xFE {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// lookupEncoding returns the encoding named name such as "shift_jis" or
+// "euc-jp". Names are case-insensitive and follow the WHATWG Encoding
+// Standard.
+func lookupEncoding(name string) (encoding.Encoding, error) {
+	enc, err := htmlindex.Get(strings.TrimSpace(name))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Unsupported encoding %s", name)
+	}
+
+	return enc, nil
+}
+
+// decodeReader converts the input read from src to UTF-8 with r. Errors are
+// reported with the byte offset of the input read so far.
+type decodeReader struct {
+	r   io.Reader
+	src *countReader
+}
+
+func newDecodeReader(r io.Reader, enc encoding.Encoding) io.Reader {
+	src := &countReader{r: r}
+
+	return &decodeReader{
+		r:   enc.NewDecoder().Reader(src),
+		src: src,
+	}
+}
+
+func (d *decodeReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	if err != nil && err != io.EOF {
+		err = errors.Wrapf(err, "Failed to decode input at byte offset %d", d.src.n)
+	}
+
+	return n, err
+}
+
+// countReader counts the bytes read from r.
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
@@ -0,0 +1,273 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestParseWithEncoding(t *testing.T) {
+	input := "TITLE: 風邪で声を失った話\n-----\nBODY:\n<p>日本語の本文</p>\n-----\n--------\n"
+
+	sjis, err := japanese.ShiftJIS.NewEncoder().String(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mts, err := ParseWithEncoding(bytes.NewBufferString(sjis), japanese.ShiftJIS)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Title != "風邪で声を失った話" {
+		t.Errorf("m.Title got %q; want %q", mts[0].Title, "風邪で声を失った話")
+	}
+
+	if mts[0].Body != "<p>日本語の本文</p>\n" {
+		t.Errorf("m.Body got %q; want %q", mts[0].Body, "<p>日本語の本文</p>\n")
+	}
+}
+
+func TestParseWithEncodingName(t *testing.T) {
+	input := "TITLE: 風邪で声を失った話\n-----\nBODY:\n<p>日本語の本文</p>\n-----\n--------\n"
+
+	var featuretests = []struct {
+		name string
+		enc  encoding.Encoding
+	}{
+		{"shift_jis", japanese.ShiftJIS},
+		{"Shift_JIS", japanese.ShiftJIS},
+		{"euc-jp", japanese.EUCJP},
+		{"utf-8", unicode.UTF8},
+	}
+
+	for _, ft := range featuretests {
+		encoded, err := ft.enc.NewEncoder().String(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mts, err := Parse(bytes.NewBufferString(encoded), WithEncoding(ft.name))
+		if err != nil {
+			t.Fatalf("%s got error %q", ft.name, err)
+		}
+
+		if mts[0].Title != "風邪で声を失った話" {
+			t.Errorf("%s m.Title got %q; want %q", ft.name, mts[0].Title, "風邪で声を失った話")
+		}
+
+		if mts[0].Body != "<p>日本語の本文</p>\n" {
+			t.Errorf("%s m.Body got %q; want %q", ft.name, mts[0].Body, "<p>日本語の本文</p>\n")
+		}
+	}
+}
+
+func TestParseWithUnknownEncoding(t *testing.T) {
+	_, err := ParseString("TITLE: title\n", WithEncoding("unknown"))
+	if err == nil || !strings.HasPrefix(err.Error(), "Unsupported encoding unknown") {
+		t.Errorf("got error %v; want Unsupported encoding", err)
+	}
+}
+
+func TestParseWithEncodingReadError(t *testing.T) {
+	sjis, err := japanese.ShiftJIS.NewEncoder().String("TITLE: 日本語\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := io.MultiReader(strings.NewReader(sjis), iotest.ErrReader(errors.New("broken")))
+
+	_, err = Parse(r, WithEncoding("shift_jis"))
+	expected := fmt.Sprintf("line 2, entry 1: Failed to read input: Failed to decode input at byte offset %d: broken", len(sjis))
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v; want %q", err, expected)
+	}
+}
+
+func TestDetectEncoding(t *testing.T) {
+	input := "TITLE: 風邪で声を失った話\n-----\nBODY:\n<p>ひらがなとカタカナ</p>\n-----\n--------\n"
+
+	var featuretests = []struct {
+		enc      encoding.Encoding
+		expected string
+	}{
+		{unicode.UTF8, EncodingUTF8},
+		{japanese.ShiftJIS, EncodingShiftJIS},
+		{japanese.EUCJP, EncodingEUCJP},
+	}
+
+	for _, ft := range featuretests {
+		encoded, err := ft.enc.NewEncoder().Bytes([]byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := DetectEncoding(encoded); got != ft.expected {
+			t.Errorf("DetectEncoding got %q; want %q", got, ft.expected)
+		}
+
+		// a character cut at the end
+		prefix, err := ft.enc.NewEncoder().String("TITLE: 風")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := DetectEncoding(encoded[:len(prefix)+1]); got != ft.expected {
+			t.Errorf("DetectEncoding of a cut character got %q; want %q", got, ft.expected)
+		}
+	}
+
+	if got := DetectEncoding([]byte{0xFF, 0xFF}); got != EncodingUTF8 {
+		t.Errorf("DetectEncoding of unknown bytes got %q; want %q", got, EncodingUTF8)
+	}
+}
+
+func TestDetectEncodingOf(t *testing.T) {
+	input := "TITLE: 風邪で声を失った話\n-----\nBODY:\n<p>ひらがなとカタカナ</p>\n-----\n--------\n"
+
+	var featuretests = []struct {
+		enc  encoding.Encoding
+		name string
+	}{
+		{unicode.UTF8, EncodingUTF8},
+		{unicode.UTF8BOM, EncodingUTF8},
+		{unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), EncodingUTF16LE},
+		{unicode.UTF16(unicode.BigEndian, unicode.UseBOM), EncodingUTF16BE},
+		{japanese.ShiftJIS, EncodingShiftJIS},
+		{japanese.EUCJP, EncodingEUCJP},
+	}
+
+	for _, ft := range featuretests {
+		encoded, err := ft.enc.NewEncoder().Bytes([]byte(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := DetectEncoding(encoded); got != ft.name {
+			t.Errorf("DetectEncoding got %q; want %q", got, ft.name)
+		}
+
+		enc := DetectEncodingOf(encoded)
+		if enc == nil {
+			t.Fatalf("DetectEncodingOf of %s got nil", ft.name)
+		}
+		decoded, err := enc.NewDecoder().Bytes(encoded)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+		if string(decoded) != input {
+			t.Errorf("DetectEncodingOf of %s should decode the input without BOM, got %q", ft.name, decoded)
+		}
+	}
+}
+
+func TestParseWithAutoEncodingUTF16(t *testing.T) {
+	input := "TITLE: 風邪で声を失った話\n-----\n--------\n"
+
+	for _, enc := range []encoding.Encoding{
+		unicode.UTF16(unicode.LittleEndian, unicode.UseBOM),
+		unicode.UTF16(unicode.BigEndian, unicode.UseBOM),
+	} {
+		encoded, err := enc.NewEncoder().String(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mts, err := ParseString(encoded, WithEncoding(CharsetAuto))
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+		if mts[0].Title != "風邪で声を失った話" {
+			t.Errorf("m.Title got %q; want %q", mts[0].Title, "風邪で声を失った話")
+		}
+	}
+}
+
+func TestParseWithAutoEncoding(t *testing.T) {
+	input := "TITLE: 風邪で声を失った話\n-----\nBODY:\n<p>日本語の本文</p>\n-----\n--------\n"
+
+	var featuretests = []struct {
+		enc      encoding.Encoding
+		expected string
+	}{
+		{unicode.UTF8, EncodingUTF8},
+		{japanese.ShiftJIS, EncodingShiftJIS},
+		{japanese.EUCJP, EncodingEUCJP},
+	}
+
+	for _, ft := range featuretests {
+		encoded, err := ft.enc.NewEncoder().String(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		detected := ""
+		mts, err := ParseString(encoded, WithEncoding(CharsetAuto), WithEncodingDetectedHandler(func(name string) {
+			detected = name
+		}))
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if detected != ft.expected {
+			t.Errorf("detected encoding got %q; want %q", detected, ft.expected)
+		}
+
+		if mts[0].Title != "風邪で声を失った話" {
+			t.Errorf("m.Title got %q; want %q", mts[0].Title, "風邪で声を失った話")
+		}
+	}
+}
+
+func TestParseShiftJISFixture(t *testing.T) {
+	b, err := os.ReadFile(filepath.Join("testdata", "shift_jis.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := DetectEncoding(b); got != EncodingShiftJIS {
+		t.Errorf("DetectEncoding got %q; want %q", got, EncodingShiftJIS)
+	}
+
+	explicit, err := ParseWithEncoding(bytes.NewReader(b), japanese.ShiftJIS)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	auto, err := ParseBytes(b, WithEncoding(CharsetAuto))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	for _, mts := range [][]*Entry{explicit, auto} {
+		if len(mts) != 1 {
+			t.Fatalf("got %d entries; want 1", len(mts))
+		}
+
+		m := mts[0]
+		if m.Title != "風邪で声を失った話" {
+			t.Errorf("m.Title got %q", m.Title)
+		}
+		if !reflect.DeepEqual(m.Category, []string{"ポエム"}) {
+			t.Errorf("m.Category got %q", m.Category)
+		}
+		if m.ExtendedBody != "<p>ｶﾀｶﾅ と　全角スペース、①や髙などの機種依存文字。</p>\n" {
+			t.Errorf("m.ExtendedBody got %q", m.ExtendedBody)
+		}
+		if len(m.Comments) != 1 || m.Comments[0].Author != "名無し" {
+			t.Errorf("m.Comments got %v", m.Comments)
+		}
+	}
+}
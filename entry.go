@@ -0,0 +1,221 @@
+package movabletype
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// GetCustomField returns the value of the custom column key.
+// It looks up CustomFields first and then Extra.
+func (e *Entry) GetCustomField(key string) (string, bool) {
+	if value, ok := e.CustomFields[key]; ok {
+		return value, true
+	}
+
+	for i := len(e.Extra) - 1; i >= 0; i-- {
+		if e.Extra[i].Key == key {
+			return e.Extra[i].Value, true
+		}
+	}
+
+	return "", false
+}
+
+// SetCustomField sets the value of the custom column key.
+// If key is in Extra, its last occurrence is updated as well so that Write
+// emits the new value.
+func (e *Entry) SetCustomField(key, value string) {
+	if e.CustomFields == nil {
+		e.CustomFields = map[string]string{}
+	}
+	e.CustomFields[key] = value
+
+	for i := len(e.Extra) - 1; i >= 0; i-- {
+		if e.Extra[i].Key == key {
+			e.Extra[i].Value = value
+			break
+		}
+	}
+}
+
+// IsPublished reports whether STATUS is Publish.
+func (e *Entry) IsPublished() bool {
+	return e.Status == StatusPublish
+}
+
+// IsDraft reports whether STATUS is Draft.
+func (e *Entry) IsDraft() bool {
+	return e.Status == StatusDraft
+}
+
+// IsFuture reports whether STATUS is Future, which is published at DATE.
+func (e *Entry) IsFuture() bool {
+	return e.Status == StatusFuture
+}
+
+// IsVisible reports whether STATUS is Publish or Future.
+func (e *Entry) IsVisible() bool {
+	return e.IsPublished() || e.IsFuture()
+}
+
+// Separator of Body and ExtendedBody in FullBody
+const FullBodySeparator = "\n"
+
+// FullBody returns Body and ExtendedBody joined with FullBodySeparator.
+// If either is empty, the other is returned as is.
+func (e *Entry) FullBody() string {
+	return e.FullBodyWithSeparator(FullBodySeparator)
+}
+
+// FullBodyWithSeparator returns Body and ExtendedBody joined with sep.
+// If either is empty, the other is returned as is.
+func (e *Entry) FullBodyWithSeparator(sep string) string {
+	if e.Body == "" || e.ExtendedBody == "" {
+		return e.Body + e.ExtendedBody
+	}
+	return e.Body + sep + e.ExtendedBody
+}
+
+// String returns a short summary of e to identify it, without bodies.
+func (e *Entry) String() string {
+	if e == nil {
+		return "<nil>"
+	}
+
+	return fmt.Sprintf("Entry{Title:%q, Author:%q, Status:%q, Date:%s, Categories:%v}",
+		e.Title, e.Author, e.Status, e.Date.Format("2006-01-02"), e.Category)
+}
+
+// Clone returns a deep copy of e. Slices and maps of the copy can be
+// modified without affecting e.
+func (e *Entry) Clone() *Entry {
+	c := *e
+
+	c.Category = cloneStrings(e.Category)
+	c.Tags = cloneStrings(e.Tags)
+	c.Images = cloneStrings(e.Images)
+
+	if e.Comments != nil {
+		c.Comments = append(make([]Comment, 0, len(e.Comments)), e.Comments...)
+	}
+	if e.Pings != nil {
+		c.Pings = append(make([]Ping, 0, len(e.Pings)), e.Pings...)
+	}
+	if e.Extra != nil {
+		c.Extra = append(make([]KeyValue, 0, len(e.Extra)), e.Extra...)
+	}
+
+	if e.CustomFields != nil {
+		c.CustomFields = make(map[string]string, len(e.CustomFields))
+		for key, value := range e.CustomFields {
+			c.CustomFields[key] = value
+		}
+	}
+	if e.ExtraBlocks != nil {
+		c.ExtraBlocks = make(map[string][]string, len(e.ExtraBlocks))
+		for name, blocks := range e.ExtraBlocks {
+			c.ExtraBlocks[name] = cloneStrings(blocks)
+		}
+	}
+
+	return &c
+}
+
+// Equal reports whether e and other have the same contents. Unlike
+// reflect.DeepEqual, the order of Category and Raw are ignored and dates
+// are compared as instants with time.Time.Equal.
+func (e *Entry) Equal(other *Entry) bool {
+	if e == nil || other == nil {
+		return e == other
+	}
+
+	return reflect.DeepEqual(e.normalized(), other.normalized())
+}
+
+// normalized returns a copy of e for Equal, with Category sorted and dates
+// in UTC.
+func (e *Entry) normalized() *Entry {
+	c := e.Clone()
+
+	sort.Strings(c.Category)
+	c.Raw = ""
+	c.Date = c.Date.UTC()
+	for i := range c.Comments {
+		c.Comments[i].Date = c.Comments[i].Date.UTC()
+	}
+	for i := range c.Pings {
+		c.Pings[i].Date = c.Pings[i].Date.UTC()
+	}
+
+	return c
+}
+
+func cloneStrings(ss []string) []string {
+	if ss == nil {
+		return nil
+	}
+	return append(make([]string, 0, len(ss)), ss...)
+}
+
+// AllCategories returns PRIMARY CATEGORY followed by the categories of
+// CATEGORY without duplicates.
+func (e *Entry) AllCategories() []string {
+	var cs []string
+	seen := map[string]bool{}
+
+	for _, c := range append([]string{e.PrimaryCategory}, e.Category...) {
+		if c == "" || seen[c] {
+			continue
+		}
+		seen[c] = true
+		cs = append(cs, c)
+	}
+
+	return cs
+}
+
+// IsInCategory reports whether cat is PRIMARY CATEGORY or one of CATEGORY.
+func (e *Entry) IsInCategory(cat string) bool {
+	if cat == "" {
+		return false
+	}
+	if e.PrimaryCategory == cat {
+		return true
+	}
+	for _, c := range e.Category {
+		if c == cat {
+			return true
+		}
+	}
+	return false
+}
+
+// images returns the values of the IMAGE columns to write. Image comes
+// first when it is set and differs from the first of Images, so that a
+// change of Image is not lost. An empty Image is returned when both are
+// empty.
+func (e *Entry) images() []string {
+	if len(e.Images) == 0 {
+		return []string{e.Image}
+	}
+	if e.Image != "" && e.Image != e.Images[0] {
+		return append([]string{e.Image}, e.Images...)
+	}
+	return e.Images
+}
+
+// hasExtra reports whether key is in Extra.
+func (e *Entry) hasExtra(key string) bool {
+	for _, kv := range e.Extra {
+		if kv.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// empty reports whether no field of e is set.
+func (e *Entry) empty() bool {
+	return reflect.DeepEqual(e, NewEntry())
+}
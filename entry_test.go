@@ -0,0 +1,329 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestCustomField(t *testing.T) {
+	m := NewEntry()
+
+	if _, ok := m.GetCustomField("FAVORITE"); ok {
+		t.Errorf("Custom field should not exist")
+	}
+
+	m.SetCustomField("FAVORITE", "1")
+
+	value, ok := m.GetCustomField("FAVORITE")
+	if !ok || value != "1" {
+		t.Errorf("GetCustomField got %q, %v; want %q, true", value, ok, "1")
+	}
+}
+
+func TestParseCaptureCustomFields(t *testing.T) {
+	input := "TITLE: title\nFAVORITE: 1\nGEO: 35.6,139.7\n-----\n--------\n"
+
+	mts, err := ParseWithOptions(bytes.NewBufferString(input), ParseOptions{CaptureCustomFields: true})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if value, _ := mts[0].GetCustomField("GEO"); value != "35.6,139.7" {
+		t.Errorf("GEO got %q; want %q", value, "35.6,139.7")
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+
+	mts, err = Parse(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].CustomFields != nil {
+		t.Errorf("By default, custom fields are ignored, got %v", mts[0].CustomFields)
+	}
+}
+
+func TestParseExtra(t *testing.T) {
+	input := "TITLE: title\nFAVORITE: 1\nGEO: 35.6,139.7\nFAVORITE: 2\n-----\n--------\n"
+
+	mts, err := Parse(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := []KeyValue{
+		{Key: "FAVORITE", Value: "1"},
+		{Key: "GEO", Value: "35.6,139.7"},
+		{Key: "FAVORITE", Value: "2"},
+	}
+	if !reflect.DeepEqual(mts[0].Extra, expected) {
+		t.Errorf("m.Extra got %v; want %v", mts[0].Extra, expected)
+	}
+
+	if mts[0].Title != "title" {
+		t.Errorf("Known columns should not be in Extra, m.Title got %q", mts[0].Title)
+	}
+
+	if value, _ := mts[0].GetCustomField("FAVORITE"); value != "2" {
+		t.Errorf("GetCustomField got %q; want %q", value, "2")
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+}
+
+func TestSetCustomFieldUpdatesExtra(t *testing.T) {
+	mts, err := Parse(bytes.NewBufferString("GEO: 35.6,139.7\n-----\n--------\n"))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	mts[0].SetCustomField("GEO", "0,0")
+	mts[0].SetCustomField("FAVORITE", "1")
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "GEO: 0,0\nFAVORITE: 1\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+}
+
+func TestParseExtraBlocks(t *testing.T) {
+	input := `TITLE: title
+-----
+BODY:
+body
+-----
+FOOTNOTES:
+note 1
+-----
+IMAGE CAPTION:
+caption 1
+-----
+IMAGE CAPTION:
+caption 2
+-----
+--------
+`
+
+	mts, err := Parse(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := map[string][]string{
+		"FOOTNOTES":     {"note 1\n"},
+		"IMAGE CAPTION": {"caption 1\n", "caption 2\n"},
+	}
+	if !reflect.DeepEqual(mts[0].ExtraBlocks, expected) {
+		t.Errorf("m.ExtraBlocks got %q; want %q", mts[0].ExtraBlocks, expected)
+	}
+
+	if mts[0].Body != "body\n" {
+		t.Errorf("m.Body got %q", mts[0].Body)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+}
+
+func TestClone(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	m := mts[0]
+	m.Tags = []string{"go"}
+	m.Comments = []Comment{{Author: "commenter"}}
+	m.ExtraBlocks = map[string][]string{"FOOTNOTES": {"note\n"}}
+	m.SetCustomField("FAVORITE", "1")
+
+	c := m.Clone()
+	if !reflect.DeepEqual(c, m) {
+		t.Fatalf("Clone got %v; want %v", c, m)
+	}
+
+	c.Category[0] = "changed"
+	c.Category = append(c.Category, "appended")
+	c.Tags[0] = "changed"
+	c.Comments[0].Author = "changed"
+	c.ExtraBlocks["FOOTNOTES"][0] = "changed"
+	c.SetCustomField("FAVORITE", "0")
+
+	if !reflect.DeepEqual(m.Category, []string{"ポエム", "技術系"}) {
+		t.Errorf("m.Category got %q; want unchanged", m.Category)
+	}
+	if m.Tags[0] != "go" || m.Comments[0].Author != "commenter" || m.ExtraBlocks["FOOTNOTES"][0] != "note\n" {
+		t.Errorf("Original entry should be unchanged, got %v", m)
+	}
+	if value, _ := m.GetCustomField("FAVORITE"); value != "1" {
+		t.Errorf("m.GetCustomField got %q; want %q", value, "1")
+	}
+}
+
+func TestEqual(t *testing.T) {
+	tokyo := time.FixedZone("Asia/Tokyo", 9*60*60)
+
+	a := NewEntry()
+	a.Title = "title"
+	a.Category = []string{"a", "b"}
+	a.Date = time.Date(2017, time.April, 9, 19, 49, 39, 0, time.UTC)
+
+	b := a.Clone()
+	b.Category = []string{"b", "a"}
+	b.Date = a.Date.In(tokyo)
+
+	if !a.Equal(b) {
+		t.Errorf("Entries with reordered categories and the same instant should be equal")
+	}
+
+	if !reflect.DeepEqual(a.Category, []string{"a", "b"}) || !reflect.DeepEqual(b.Category, []string{"b", "a"}) {
+		t.Errorf("Equal should not modify entries, got %q and %q", a.Category, b.Category)
+	}
+
+	b.Title = "other"
+	if a.Equal(b) {
+		t.Errorf("Entries with different titles should not be equal")
+	}
+
+	var n *Entry
+	if a.Equal(nil) || n.Equal(a) {
+		t.Errorf("nil and non-nil entries should not be equal")
+	}
+	if !n.Equal(nil) {
+		t.Errorf("nil entries should be equal")
+	}
+}
+
+func TestString(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `Entry{Title:"ポエム", Author:"catatsuy", Status:"Publish", Date:2017-04-22, Categories:[ポエム 技術系]}`
+	if mts[0].String() != expected {
+		t.Errorf("m.String() got %s; want %s", mts[0].String(), expected)
+	}
+
+	var m *Entry
+	if fmt.Sprint(m) != "<nil>" {
+		t.Errorf("nil entry got %s; want <nil>", fmt.Sprint(m))
+	}
+}
+
+func TestFullBody(t *testing.T) {
+	var featuretests = []struct {
+		body, extended string
+		expected       string
+	}{
+		{"<p>body</p>\n", "<p>extended</p>\n", "<p>body</p>\n\n<p>extended</p>\n"},
+		{"<p>body</p>\n", "", "<p>body</p>\n"},
+		{"", "<p>extended</p>\n", "<p>extended</p>\n"},
+		{"", "", ""},
+	}
+
+	for _, ft := range featuretests {
+		m := NewEntry()
+		m.Body = ft.body
+		m.ExtendedBody = ft.extended
+
+		if m.FullBody() != ft.expected {
+			t.Errorf("FullBody got %q; want %q", m.FullBody(), ft.expected)
+		}
+	}
+
+	m := NewEntry()
+	m.Body = "body"
+	m.ExtendedBody = "extended"
+	if got := m.FullBodyWithSeparator("<!--more-->"); got != "body<!--more-->extended" {
+		t.Errorf("FullBodyWithSeparator got %q; want %q", got, "body<!--more-->extended")
+	}
+}
+
+func TestStatusMethods(t *testing.T) {
+	var featuretests = []struct {
+		status                            Status
+		published, draft, future, visible bool
+	}{
+		{StatusPublish, true, false, false, true},
+		{StatusDraft, false, true, false, false},
+		{StatusFuture, false, false, true, true},
+		{"", false, false, false, false},
+	}
+
+	for _, ft := range featuretests {
+		m := NewEntry()
+		m.Status = ft.status
+
+		if m.IsPublished() != ft.published || m.IsDraft() != ft.draft || m.IsFuture() != ft.future || m.IsVisible() != ft.visible {
+			t.Errorf("STATUS %q got %v %v %v %v; want %v %v %v %v", ft.status,
+				m.IsPublished(), m.IsDraft(), m.IsFuture(), m.IsVisible(),
+				ft.published, ft.draft, ft.future, ft.visible)
+		}
+	}
+}
+
+func TestAllCategories(t *testing.T) {
+	m := NewEntry()
+	m.PrimaryCategory = "技術系"
+	m.Category = []string{"ポエム", "技術系", "ブログ"}
+
+	expected := []string{"技術系", "ポエム", "ブログ"}
+	if !reflect.DeepEqual(m.AllCategories(), expected) {
+		t.Errorf("m.AllCategories() got %q; want %q", m.AllCategories(), expected)
+	}
+
+	var featuretests = []struct {
+		cat      string
+		expected bool
+	}{
+		{"技術系", true},
+		{"ポエム", true},
+		{"日記", false},
+		{"", false},
+	}
+
+	for _, ft := range featuretests {
+		if m.IsInCategory(ft.cat) != ft.expected {
+			t.Errorf("m.IsInCategory(%q) got %v; want %v", ft.cat, !ft.expected, ft.expected)
+		}
+	}
+
+	if NewEntry().AllCategories() != nil {
+		t.Errorf("AllCategories of an entry without categories should be nil")
+	}
+}
@@ -0,0 +1,124 @@
+package movabletype
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Errors matched by ParseError with errors.Is
+var (
+	ErrInvalidStatus        = errors.New("invalid STATUS")
+	ErrInvalidAllowComments = errors.New("invalid ALLOW COMMENTS")
+	ErrInvalidAllowPings    = errors.New("invalid ALLOW PINGS")
+	ErrInvalidConvertBreaks = errors.New("invalid CONVERT BREAKS")
+	ErrInvalidNoEntry       = errors.New("invalid NO ENTRY")
+	ErrInvalidDate          = errors.New("invalid DATE")
+
+	// ErrRead is a failure to read the input, such as an error of the
+	// io.Reader or a line longer than MaxLineSize
+	ErrRead = errors.New("failed to read input")
+)
+
+// ParseError is an error on a column of the input.
+// Errors returned by Parse can be retrieved as ParseError with errors.As.
+type ParseError struct {
+	// Line number (1-based) where the error occurred
+	Line int
+
+	// Entry number (1-based) in the input where the error occurred
+	EntryIndex int
+
+	// Column name such as STATUS or DATE
+	Field string
+
+	// Value is the offending value of the column, if any
+	Value string
+
+	// Reason describes the error
+	Reason string
+
+	// Err is the underlying error, if any
+	Err error
+
+	// whether the error is a failure to read the input
+	read bool
+}
+
+func (e *ParseError) Error() string {
+	msg := e.Reason
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d, entry %d: %s", e.Line, e.EntryIndex, msg)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of Field, such as
+// ErrInvalidDate for DATE and the DATE of COMMENT and PING blocks, or
+// ErrRead for a failure to read the input.
+func (e *ParseError) Is(target error) bool {
+	switch target {
+	case ErrInvalidStatus:
+		return e.Field == "STATUS"
+	case ErrInvalidAllowComments:
+		return e.Field == "ALLOW COMMENTS"
+	case ErrInvalidAllowPings:
+		return e.Field == "ALLOW PINGS"
+	case ErrInvalidConvertBreaks:
+		return e.Field == "CONVERT BREAKS"
+	case ErrInvalidNoEntry:
+		return e.Field == "NO ENTRY"
+	case ErrInvalidDate:
+		return e.Field == "DATE" || e.Field == "COMMENT" || e.Field == "PING"
+	case ErrRead:
+		return e.read
+	}
+	return false
+}
+
+// MultiError is a list of errors returned with ContinueOnError
+type MultiError []error
+
+func (e MultiError) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+
+	ss := make([]string, 0, len(e))
+	for _, err := range e {
+		ss = append(ss, err.Error())
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(e), strings.Join(ss, "; "))
+}
+
+// Unwrap returns the errors for errors.Is and errors.As.
+func (e MultiError) Unwrap() []error {
+	return e
+}
+
+// IsParseError reports whether err is or wraps ParseError.
+func IsParseError(err error) bool {
+	var pe *ParseError
+	return errors.As(err, &pe)
+}
+
+// withPosition sets line and entry to ParseError in err.
+func withPosition(err error, line, entry int) error {
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		pe.Line = line
+		pe.EntryIndex = entry
+	}
+	return err
+}
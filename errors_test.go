@@ -0,0 +1,161 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/pkg/errors"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestParseError(t *testing.T) {
+	var featuretests = []struct {
+		input string
+		line  int
+		field string
+		value string
+	}{
+		{"TITLE: title\nSTATUS: Published\n--------\n", 2, "STATUS", "Published"},
+		{"TITLE: title\n--------\nALLOW COMMENTS: yes\n--------\n", 3, "ALLOW COMMENTS", "yes"},
+		{"ALLOW PINGS: 3\n--------\n", 1, "ALLOW PINGS", "3"},
+		{"TITLE: title\n-----\nBODY:\nbody\n-----\nDATE: yesterday\n--------\n", 6, "DATE", "yesterday"},
+		{"TITLE: title\n-----\nCOMMENT:\nAUTHOR: Foo\nDATE: yesterday\nbody\n-----\n--------\n", 3, "COMMENT", "yesterday"},
+		{"TITLE: title\n-----\nPING:\nDATE: yesterday\n-----\n--------\n", 3, "PING", "yesterday"},
+	}
+
+	for _, ft := range featuretests {
+		_, err := Parse(bytes.NewBufferString(ft.input))
+
+		if !IsParseError(err) {
+			t.Fatalf("got %v; want ParseError", err)
+		}
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("errors.As failed for %v", err)
+		}
+
+		if pe.Line != ft.line || pe.Field != ft.field || pe.Value != ft.value {
+			t.Errorf("got line %d field %q value %q; want line %d field %q value %q", pe.Line, pe.Field, pe.Value, ft.line, ft.field, ft.value)
+		}
+	}
+}
+
+func TestParseErrorUnwrap(t *testing.T) {
+	_, err := Parse(bytes.NewBufferString("ALLOW COMMENTS: yes\n--------\n"))
+
+	var ne *strconv.NumError
+	if !errors.As(err, &ne) {
+		t.Errorf("ParseError should wrap *strconv.NumError, got %v", err)
+	}
+
+	if err.Error() != `line 1, entry 1: ALLOW COMMENTS column is allowed only 0, 1 or 2: strconv.Atoi: parsing "yes": invalid syntax` {
+		t.Errorf("Error message got %q", err)
+	}
+}
+
+func TestIsParseError(t *testing.T) {
+	if IsParseError(errors.New("error")) {
+		t.Errorf("IsParseError should be false for other errors")
+	}
+
+	if IsParseError(nil) {
+		t.Errorf("IsParseError should be false for nil")
+	}
+}
+
+func TestParseErrorPosition(t *testing.T) {
+	input := sampleExport + "TITLE: third\nSTATUS: Publish\nDATE: 04/31/2017 25:00:00\n-----\n--------\n"
+
+	_, err := ParseString(input)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v; want ParseError", err)
+	}
+
+	if pe.Line != 35 || pe.EntryIndex != 3 || pe.Field != "DATE" {
+		t.Errorf("got line %d entry %d field %q; want line 35 entry 3 field DATE", pe.Line, pe.EntryIndex, pe.Field)
+	}
+
+	if !strings.HasPrefix(err.Error(), "line 35, entry 3: Parsing error on DATE column: ") {
+		t.Errorf("Error message got %q", err)
+	}
+
+	// Skipped entries are counted with ContinueOnError as well
+	_, err = ParseWithOptions(strings.NewReader("STATUS: Spam\n--------\n"+input), ParseOptions{ContinueOnError: true})
+
+	var me MultiError
+	if !errors.As(err, &me) || len(me) != 2 {
+		t.Fatalf("got %v; want MultiError of 2 errors", err)
+	}
+
+	if !errors.As(me[1], &pe) || pe.Line != 37 || pe.EntryIndex != 4 {
+		t.Errorf("got %v; want line 37 entry 4", me[1])
+	}
+}
+
+func TestParseErrorIs(t *testing.T) {
+	var featuretests = []struct {
+		input  string
+		target error
+	}{
+		{"STATUS: Published\n--------\n", ErrInvalidStatus},
+		{"ALLOW COMMENTS: yes\n--------\n", ErrInvalidAllowComments},
+		{"ALLOW COMMENTS: 3\n--------\n", ErrInvalidAllowComments},
+		{"ALLOW PINGS: 3\n--------\n", ErrInvalidAllowPings},
+		{"NO ENTRY: yes\n--------\n", ErrInvalidNoEntry},
+		{"DATE: yesterday\n--------\n", ErrInvalidDate},
+		{"TITLE: title\n-----\nCOMMENT:\nDATE: yesterday\n-----\n--------\n", ErrInvalidDate},
+		{"TITLE: title\n-----\nPING:\nDATE: yesterday\n-----\n--------\n", ErrInvalidDate},
+	}
+
+	sentinels := []error{ErrInvalidStatus, ErrInvalidAllowComments, ErrInvalidAllowPings, ErrInvalidNoEntry, ErrInvalidDate, ErrRead}
+
+	for _, ft := range featuretests {
+		_, err := ParseString(ft.input)
+
+		for _, target := range sentinels {
+			if errors.Is(err, target) != (target == ft.target) {
+				t.Errorf("errors.Is(%q, %q) got %v", err, target, !(target == ft.target))
+			}
+		}
+	}
+
+	_, err := ParseString("CONVERT BREAKS: wiki\n--------\n", func(o *ParseOptions) { o.StrictConvertBreaks = true })
+	if !errors.Is(err, ErrInvalidConvertBreaks) {
+		t.Errorf("errors.Is(%q, ErrInvalidConvertBreaks) got false", err)
+	}
+
+	// ContinueOnError
+	_, err = ParseWithOptions(bytes.NewBufferString("DATE: yesterday\n--------\n"), ParseOptions{ContinueOnError: true})
+	if !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("errors.Is(%q, ErrInvalidDate) got false", err)
+	}
+
+	// Failures to read the input
+	readErrors := []error{}
+	_, err = ParseString("TITLE: "+strings.Repeat("a", 100)+"\n--------\n", WithMaxLineSize(64))
+	readErrors = append(readErrors, err)
+	_, err = Parse(iotest.ErrReader(io.ErrUnexpectedEOF))
+	readErrors = append(readErrors, err)
+
+	for _, err := range readErrors {
+		if !errors.Is(err, ErrRead) {
+			t.Errorf("errors.Is(%q, ErrRead) got false", err)
+		}
+		if errors.Is(err, ErrInvalidDate) {
+			t.Errorf("errors.Is(%q, ErrInvalidDate) got true", err)
+		}
+	}
+
+	_, err = ParseString("TITLE: title\n-----\n--------\n", WithValidator(func(e *Entry) error { return errors.New("invalid") }))
+	if errors.Is(err, ErrRead) {
+		t.Errorf("errors.Is(%q, ErrRead) of a validation error got true", err)
+	}
+}
@@ -0,0 +1,4 @@
+package movabletype
+
+// NewParserOfParse creates Parser as Parse and ParseWithOptions do.
+var NewParserOfParse = newParser
@@ -0,0 +1,70 @@
+package movabletype
+
+import "time"
+
+// FilterByStatus returns the entries whose STATUS is status. It takes a
+// string so that a value such as a command line flag can be passed as is;
+// pass string(StatusPublish) for the Status constants.
+func FilterByStatus(entries []*Entry, status string) []*Entry {
+	return Filter(entries, func(e *Entry) bool {
+		return string(e.Status) == status
+	})
+}
+
+// FilterByAuthor returns the entries whose AUTHOR is author.
+func FilterByAuthor(entries []*Entry, author string) []*Entry {
+	return Filter(entries, func(e *Entry) bool {
+		return e.Author == author
+	})
+}
+
+// FilterByCategory returns the entries in category, either as PRIMARY
+// CATEGORY or CATEGORY.
+func FilterByCategory(entries []*Entry, category string) []*Entry {
+	return Filter(entries, func(e *Entry) bool {
+		return e.IsInCategory(category)
+	})
+}
+
+// FilterByDateRange returns the entries whose DATE is between from and to,
+// inclusive.
+func FilterByDateRange(entries []*Entry, from, to time.Time) []*Entry {
+	return Filter(entries, func(e *Entry) bool {
+		return !e.Date.Before(from) && !e.Date.After(to)
+	})
+}
+
+// Filter returns a new slice of the entries for which fn returns true.
+// entries is not modified, so the result can be passed to another Filter
+// function such as FilterByStatus.
+func Filter(entries []*Entry, fn func(e *Entry) bool) []*Entry {
+	filtered := []*Entry{}
+
+	for _, e := range entries {
+		if fn(e) {
+			filtered = append(filtered, e)
+		}
+	}
+
+	return filtered
+}
+
+// DuplicateBasenames returns each BASENAME used by more than one of entries
+// with the indices of the entries. Entries without BASENAME are ignored.
+func DuplicateBasenames(entries []*Entry) map[string][]int {
+	indices := map[string][]int{}
+	for i, e := range entries {
+		if e.Basename != "" {
+			indices[e.Basename] = append(indices[e.Basename], i)
+		}
+	}
+
+	dups := map[string][]int{}
+	for basename, is := range indices {
+		if len(is) > 1 {
+			dups[basename] = is
+		}
+	}
+
+	return dups
+}
@@ -0,0 +1,81 @@
+package movabletype_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func filterFixture() []*Entry {
+	return []*Entry{
+		{Title: "1", Author: "catatsuy", Status: "Publish", PrimaryCategory: "ブログ", Category: []string{"ポエム"}, Date: time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "2", Author: "foo", Status: "Draft", Category: []string{"ブログ"}, Date: time.Date(2017, time.April, 15, 0, 0, 0, 0, time.UTC)},
+		{Title: "3", Author: "catatsuy", Status: "Publish", Category: []string{"日常"}, Date: time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func titles(entries []*Entry) []string {
+	ss := []string{}
+	for _, e := range entries {
+		ss = append(ss, e.Title)
+	}
+	return ss
+}
+
+func TestFilter(t *testing.T) {
+	entries := filterFixture()
+	status := "Draft"
+
+	var featuretests = []struct {
+		name     string
+		filtered []*Entry
+		titles   []string
+	}{
+		{"FilterByStatus", FilterByStatus(entries, "Publish"), []string{"1", "3"}},
+		{"FilterByStatus string", FilterByStatus(entries, status), []string{"2"}},
+		{"FilterByStatus none", FilterByStatus(entries, "Future"), []string{}},
+		{"FilterByAuthor", FilterByAuthor(entries, "foo"), []string{"2"}},
+		{"FilterByCategory", FilterByCategory(entries, "ブログ"), []string{"1", "2"}},
+		{"FilterByCategory none", FilterByCategory(entries, "技術系"), []string{}},
+		{"FilterByDateRange", FilterByDateRange(entries, time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC), time.Date(2017, time.April, 30, 0, 0, 0, 0, time.UTC)), []string{"1", "2"}},
+		{"Filter", Filter(entries, func(e *Entry) bool { return e.Date.Month() == time.May }), []string{"3"}},
+		{"Filter composed", FilterByCategory(FilterByStatus(entries, string(StatusPublish)), "ブログ"), []string{"1"}},
+		{"Filter composed none", FilterByAuthor(FilterByStatus(entries, string(StatusDraft)), "catatsuy"), []string{}},
+		{"Filter nil", Filter(nil, func(e *Entry) bool { return true }), []string{}},
+	}
+
+	for _, ft := range featuretests {
+		got := titles(ft.filtered)
+		if len(got) != len(ft.titles) {
+			t.Errorf("%s got %q; want %q", ft.name, got, ft.titles)
+			continue
+		}
+		for i := range got {
+			if got[i] != ft.titles[i] {
+				t.Errorf("%s got %q; want %q", ft.name, got, ft.titles)
+				break
+			}
+		}
+	}
+
+	if len(entries) != 3 || entries[1].Title != "2" {
+		t.Errorf("Input should be untouched, got %q", titles(entries))
+	}
+}
+
+func TestDuplicateBasenames(t *testing.T) {
+	entries := []*Entry{
+		{Title: "1", Basename: "poem"},
+		{Title: "2", Basename: "2017/04/09/194939"},
+		{Title: "3", Basename: "poem"},
+		{Title: "4"},
+		{Title: "5"},
+	}
+
+	expected := map[string][]int{"poem": {0, 2}}
+	if got := DuplicateBasenames(entries); !reflect.DeepEqual(got, expected) {
+		t.Errorf("DuplicateBasenames got %v; want %v", got, expected)
+	}
+}
@@ -0,0 +1,37 @@
+package movabletype
+
+// EntriesByCategory groups entries by category. An entry is in every group
+// of PRIMARY CATEGORY and CATEGORY it has.
+func EntriesByCategory(entries []*Entry) map[string][]*Entry {
+	groups := map[string][]*Entry{}
+	for _, e := range entries {
+		for _, c := range e.AllCategories() {
+			groups[c] = append(groups[c], e)
+		}
+	}
+	return groups
+}
+
+// EntriesByAuthor groups entries by AUTHOR. Entries without AUTHOR are
+// omitted.
+func EntriesByAuthor(entries []*Entry) map[string][]*Entry {
+	groups := map[string][]*Entry{}
+	for _, e := range entries {
+		if e.Author != "" {
+			groups[e.Author] = append(groups[e.Author], e)
+		}
+	}
+	return groups
+}
+
+// EntriesByStatus groups entries by STATUS. Entries without STATUS are
+// omitted.
+func EntriesByStatus(entries []*Entry) map[Status][]*Entry {
+	groups := map[Status][]*Entry{}
+	for _, e := range entries {
+		if e.Status != "" {
+			groups[e.Status] = append(groups[e.Status], e)
+		}
+	}
+	return groups
+}
@@ -0,0 +1,61 @@
+package movabletype_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestEntriesByCategory(t *testing.T) {
+	groups := EntriesByCategory(filterFixture())
+
+	expected := map[string][]string{
+		"ブログ": {"1", "2"},
+		"ポエム": {"1"},
+		"日常":  {"3"},
+	}
+
+	got := map[string][]string{}
+	for c, entries := range groups {
+		got[c] = titles(entries)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EntriesByCategory got %v; want %v", got, expected)
+	}
+}
+
+func TestEntriesByAuthor(t *testing.T) {
+	entries := append(filterFixture(), &Entry{Title: "4"})
+	groups := EntriesByAuthor(entries)
+
+	expected := map[string][]string{
+		"catatsuy": {"1", "3"},
+		"foo":      {"2"},
+	}
+
+	got := map[string][]string{}
+	for author, entries := range groups {
+		got[author] = titles(entries)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EntriesByAuthor got %v; want %v", got, expected)
+	}
+}
+
+func TestEntriesByStatus(t *testing.T) {
+	groups := EntriesByStatus(filterFixture())
+
+	expected := map[Status][]string{
+		StatusPublish: {"1", "3"},
+		StatusDraft:   {"2"},
+	}
+
+	got := map[Status][]string{}
+	for status, entries := range groups {
+		got[status] = titles(entries)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("EntriesByStatus got %v; want %v", got, expected)
+	}
+}
@@ -0,0 +1,162 @@
+package movabletype
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// entryJSON overrides the JSON representation of some fields of Entry.
+type entryJSON struct {
+	*entry
+
+	// null if it is DefaultAllowComments
+	AllowComments *int `json:"allow_comments"`
+
+	// null if it is DefaultAllowPings
+	AllowPings *int `json:"allow_pings"`
+
+	// RFC 3339. Omitted if it is zero.
+	Date string `json:"date,omitempty"`
+}
+
+// entry has the fields of Entry without its methods.
+type entry Entry
+
+// MarshalJSON implements json.Marshaler.
+// Date is formatted in RFC 3339 and ALLOW COMMENTS / ALLOW PINGS left at
+// their default are null. Other empty fields are omitted.
+// It has a value receiver so that Entry values are marshaled the same as
+// pointers.
+func (e Entry) MarshalJSON() ([]byte, error) {
+	j := entryJSON{entry: (*entry)(&e)}
+
+	if e.AllowComments != DefaultAllowComments {
+		j.AllowComments = &e.AllowComments
+	}
+	if e.AllowPings != DefaultAllowPings {
+		j.AllowPings = &e.AllowPings
+	}
+	if !e.Date.IsZero() {
+		j.Date = e.Date.Format(time.RFC3339)
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// ALLOW COMMENTS / ALLOW PINGS which are null or missing are set to their
+// default.
+func (e *Entry) UnmarshalJSON(data []byte) error {
+	*e = Entry{}
+
+	j := entryJSON{entry: (*entry)(e)}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	e.AllowComments = DefaultAllowComments
+	if j.AllowComments != nil {
+		e.AllowComments = *j.AllowComments
+	}
+
+	e.AllowPings = DefaultAllowPings
+	if j.AllowPings != nil {
+		e.AllowPings = *j.AllowPings
+	}
+
+	if j.Date != "" {
+		var err error
+		e.Date, err = time.Parse(time.RFC3339, j.Date)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// commentJSON overrides the JSON representation of Date of Comment.
+type commentJSON struct {
+	*comment
+
+	// RFC 3339. Omitted if it is zero.
+	Date string `json:"date,omitempty"`
+}
+
+// comment has the fields of Comment without its methods.
+type comment Comment
+
+// MarshalJSON implements json.Marshaler.
+// Date is formatted in RFC 3339 and omitted if it is zero as with Entry.
+func (c Comment) MarshalJSON() ([]byte, error) {
+	j := commentJSON{comment: (*comment)(&c)}
+
+	if !c.Date.IsZero() {
+		j.Date = c.Date.Format(time.RFC3339)
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (c *Comment) UnmarshalJSON(data []byte) error {
+	*c = Comment{}
+
+	j := commentJSON{comment: (*comment)(c)}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	if j.Date != "" {
+		var err error
+		c.Date, err = time.Parse(time.RFC3339, j.Date)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// pingJSON overrides the JSON representation of Date of Ping.
+type pingJSON struct {
+	*ping
+
+	// RFC 3339. Omitted if it is zero.
+	Date string `json:"date,omitempty"`
+}
+
+// ping has the fields of Ping without its methods.
+type ping Ping
+
+// MarshalJSON implements json.Marshaler.
+// Date is formatted in RFC 3339 and omitted if it is zero as with Entry.
+func (pg Ping) MarshalJSON() ([]byte, error) {
+	j := pingJSON{ping: (*ping)(&pg)}
+
+	if !pg.Date.IsZero() {
+		j.Date = pg.Date.Format(time.RFC3339)
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pg *Ping) UnmarshalJSON(data []byte) error {
+	*pg = Ping{}
+
+	j := pingJSON{ping: (*ping)(pg)}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	if j.Date != "" {
+		var err error
+		pg.Date, err = time.Parse(time.RFC3339, j.Date)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
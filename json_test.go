@@ -0,0 +1,147 @@
+package movabletype_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestEntryJSON(t *testing.T) {
+	m := NewEntry()
+	m.Author = "catatsuy"
+	m.Title = "ポエム"
+	m.Status = "Publish"
+	m.AllowComments = 1
+	m.AllowPings = 0
+	m.Date = time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)
+	m.Category = []string{"ポエム", "技術系"}
+	m.Body = "<p>body</p>\n"
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `{"author":"catatsuy","title":"ポエム","status":"Publish","category":["ポエム","技術系"],"body":"\u003cp\u003ebody\u003c/p\u003e\n","allow_comments":1,"allow_pings":0,"date":"2017-04-22T20:41:58Z"}`
+	if string(b) != expected {
+		t.Errorf("Error marshaling, expected %s; got %s", expected, b)
+	}
+
+	got := &Entry{}
+	err = json.Unmarshal(b, got)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Error unmarshaling, expected %v; got %v", m, got)
+	}
+}
+
+func TestEntryJSONDefaults(t *testing.T) {
+	m := NewEntry()
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `{"allow_comments":null,"allow_pings":null}`
+	if string(b) != expected {
+		t.Errorf("Error marshaling, expected %s; got %s", expected, b)
+	}
+
+	got := &Entry{}
+	err = json.Unmarshal(b, got)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Error unmarshaling, expected %v; got %v", m, got)
+	}
+}
+
+func TestEntryJSONValue(t *testing.T) {
+	m := NewEntry()
+	m.Title = "title"
+
+	ptr, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	value, err := json.Marshal(*m)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if string(value) != string(ptr) {
+		t.Errorf("Entry value should be marshaled like a pointer, expected %s; got %s", ptr, value)
+	}
+
+	// Entries in a slice of values and as struct fields
+	b, err := json.Marshal(struct{ Entries []Entry }{[]Entry{*m}})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if expected := `{"Entries":[` + string(ptr) + `]}`; string(b) != expected {
+		t.Errorf("Error marshaling, expected %s; got %s", expected, b)
+	}
+}
+
+func TestCommentJSON(t *testing.T) {
+	m := NewEntry()
+	m.Comments = []Comment{{Author: "Foo", Body: "comment\n"}}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `{"comments":[{"author":"Foo","body":"comment\n"}],"allow_comments":null,"allow_pings":null}`
+	if string(b) != expected {
+		t.Errorf("Error marshaling, expected %s; got %s", expected, b)
+	}
+
+	got := &Entry{}
+	err = json.Unmarshal(b, got)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Error unmarshaling, expected %v; got %v", m, got)
+	}
+}
+
+func TestCommentPingJSONDate(t *testing.T) {
+	d := time.Date(2017, time.April, 9, 20, 0, 0, 0, time.UTC)
+
+	m := NewEntry()
+	m.Comments = []Comment{{Author: "Foo", Date: d}, {Author: "Bar"}}
+	m.Pings = []Ping{{Title: "Foo", Date: d}, {Title: "Bar"}}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `{"comments":[{"author":"Foo","date":"2017-04-09T20:00:00Z"},{"author":"Bar"}],"pings":[{"title":"Foo","date":"2017-04-09T20:00:00Z"},{"title":"Bar"}],"allow_comments":null,"allow_pings":null}`
+	if string(b) != expected {
+		t.Errorf("Error marshaling, expected %s; got %s", expected, b)
+	}
+
+	got := &Entry{}
+	err = json.Unmarshal(b, got)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("Error unmarshaling, expected %v; got %v", m, got)
+	}
+}
@@ -0,0 +1,235 @@
+package movabletype
+
+import (
+	"bufio"
+	"html"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WriteMarkdown writes e to w as a Markdown file for static site
+// generators such as Hugo and Jekyll. The file starts with the front
+// matter of ToFrontMatter, followed by Body and ExtendedBody as is.
+func WriteMarkdown(w io.Writer, e *Entry) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(e.ToFrontMatter())
+
+	bw.WriteString(e.Body)
+	if e.ExtendedBody != "" {
+		if e.Body != "" && !strings.HasSuffix(e.Body, "\n") {
+			bw.WriteString("\n")
+		}
+		bw.WriteString(e.ExtendedBody)
+	}
+
+	return bw.Flush()
+}
+
+// WriteMarkdownFiles writes each of entries to a Markdown file in dir with
+// WriteMarkdown. Files are named after BASENAME with "/" replaced by "-",
+// such as 2017-04-09-194939.md. Entries without BASENAME are named after
+// their index such as entry-1.md.
+func WriteMarkdownFiles(dir string, entries []*Entry) error {
+	names := map[string]bool{}
+
+	for i, e := range entries {
+		name := markdownFileName(e, i)
+		if names[name] {
+			return errors.Errorf("Duplicated file name %s", name)
+		}
+		names[name] = true
+
+		err := writeMarkdownFile(filepath.Join(dir, name), e)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeMarkdownFile(path string, e *Entry) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to create %s", path)
+	}
+
+	err = WriteMarkdown(f, e)
+	if err != nil {
+		f.Close()
+		return errors.Wrapf(err, "Failed to write %s", path)
+	}
+
+	return f.Close()
+}
+
+// markdownFileName returns the file name of the i-th entry e.
+func markdownFileName(e *Entry, i int) string {
+	name := strings.Trim(strings.Replace(e.Basename, "/", "-", -1), "-. ")
+	if name == "" {
+		name = "entry-" + strconv.Itoa(i+1)
+	}
+
+	return name + ".md"
+}
+
+// ToFrontMatter returns YAML front matter of e for Hugo and Jekyll,
+// delimited by "---" lines. It has title, date, author, slug from BASENAME,
+// categories, tags and draft. Empty values other than title and draft are
+// omitted.
+func (e *Entry) ToFrontMatter() string {
+	sb := &strings.Builder{}
+
+	sb.WriteString("---\n")
+	sb.WriteString("title: " + strconv.Quote(e.Title) + "\n")
+	if !e.Date.IsZero() {
+		sb.WriteString("date: " + e.Date.Format(time.RFC3339) + "\n")
+	}
+	if e.Author != "" {
+		sb.WriteString("author: " + strconv.Quote(e.Author) + "\n")
+	}
+	if e.Basename != "" {
+		sb.WriteString("slug: " + strconv.Quote(e.Basename) + "\n")
+	}
+	writeYAMLList(sb, "categories", e.AllCategories())
+	writeYAMLList(sb, "tags", e.Tags)
+	sb.WriteString("draft: " + strconv.FormatBool(e.IsDraft()) + "\n")
+	sb.WriteString("---\n")
+
+	return sb.String()
+}
+
+// ToHugoContent returns the content of a Hugo content file, which is
+// ToFrontMatter followed by ToMarkdown.
+func (e *Entry) ToHugoContent() string {
+	return e.ToFrontMatter() + e.ToMarkdown()
+}
+
+// ToMarkdown returns Body and ExtendedBody converted from HTML to Markdown,
+// separated by a blank line. Bodies are returned as is when CONVERT BREAKS
+// is markdown or markdown_with_smartypants.
+//
+// The conversion keeps the following constructs:
+//
+//   - <a href> as [text](url)
+//   - <img src alt> as ![alt](src)
+//   - <strong> and <b> as **text**, <em> and <i> as *text*
+//   - <code> as `text`
+//   - <h1> to <h6> as # to ###### headings
+//   - <li> as "- " list items
+//   - <br> as a line break, and block tags such as <p> and <div> as
+//     paragraph breaks
+//
+// Other tags are removed keeping their text and entities are unescaped.
+// Nested lists, tables and the indentation of <pre> are not preserved.
+func (e *Entry) ToMarkdown() string {
+	body, extended := e.Body, e.ExtendedBody
+	if e.ConvertBreaks != ConvertBreaksMarkdown && e.ConvertBreaks != ConvertBreaksMarkdownWithSmartyPants {
+		body, extended = htmlToMarkdown(body), htmlToMarkdown(extended)
+	}
+
+	body = strings.TrimRight(body, "\n")
+	extended = strings.TrimRight(extended, "\n")
+	if body == "" || extended == "" {
+		return withNewline(body + extended)
+	}
+	return body + "\n\n" + extended + "\n"
+}
+
+var (
+	markdownImageRegexp   = regexp.MustCompile(`(?is)<img\s[^>]*>`)
+	markdownAttrRegexp    = regexp.MustCompile(`(?is)\b(src|alt)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	markdownLinkRegexp    = regexp.MustCompile(`(?is)<a\s[^>]*?\bhref\s*=\s*(?:"([^"]*)"|'([^']*)')[^>]*>(.*?)</a\s*>`)
+	markdownHeadingRegexp = regexp.MustCompile(`(?is)<h([1-6])(?:\s[^>]*)?>(.*?)</h[1-6]\s*>`)
+	markdownItemRegexp    = regexp.MustCompile(`(?i)\s*<li(?:\s[^>]*)?>`)
+	markdownItemEndRegexp = regexp.MustCompile(`(?i)</li\s*>`)
+	markdownBreakRegexp   = regexp.MustCompile(`(?i)<br\s*/?>[ \t]*\n?`)
+)
+
+// Inline tags converted by htmlToMarkdown and their Markdown markers
+var markdownInlineTags = []struct {
+	re     *regexp.Regexp
+	marker string
+}{
+	{regexp.MustCompile(`(?is)<(?:strong|b)(?:\s[^>]*)?>(.*?)</(?:strong|b)\s*>`), "**"},
+	{regexp.MustCompile(`(?is)<(?:em|i)(?:\s[^>]*)?>(.*?)</(?:em|i)\s*>`), "*"},
+	{regexp.MustCompile(`(?is)<code(?:\s[^>]*)?>(.*?)</code\s*>`), "`"},
+}
+
+// htmlToMarkdown converts HTML s to Markdown as described in ToMarkdown.
+func htmlToMarkdown(s string) string {
+	if s == "" {
+		return ""
+	}
+
+	s = markdownImageRegexp.ReplaceAllStringFunc(s, func(tag string) string {
+		attrs := map[string]string{}
+		for _, m := range markdownAttrRegexp.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(m[1])] = m[2] + m[3]
+		}
+		return "![" + attrs["alt"] + "](" + attrs["src"] + ")"
+	})
+	s = markdownLinkRegexp.ReplaceAllStringFunc(s, func(tag string) string {
+		m := markdownLinkRegexp.FindStringSubmatch(tag)
+		return "[" + m[3] + "](" + m[1] + m[2] + ")"
+	})
+	for _, t := range markdownInlineTags {
+		s = t.re.ReplaceAllString(s, t.marker+"${1}"+t.marker)
+	}
+	s = markdownHeadingRegexp.ReplaceAllStringFunc(s, func(tag string) string {
+		m := markdownHeadingRegexp.FindStringSubmatch(tag)
+		level, _ := strconv.Atoi(m[1])
+		return "\n\n" + strings.Repeat("#", level) + " " + m[2] + "\n\n"
+	})
+	s = markdownItemEndRegexp.ReplaceAllString(s, "")
+	s = markdownItemRegexp.ReplaceAllString(s, "\n- ")
+	s = markdownBreakRegexp.ReplaceAllString(s, "\n")
+	s = htmlTagRegexp.ReplaceAllStringFunc(s, func(tag string) string {
+		if m := htmlTagNameRegexp.FindStringSubmatch(tag); m != nil && blockTags[strings.ToLower(m[1])] {
+			return "\n\n"
+		}
+		return ""
+	})
+	s = html.UnescapeString(s)
+
+	// Trim lines and collapse blank lines into one
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" && (len(lines) == 0 || lines[len(lines)-1] == "") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return withNewline(strings.TrimRight(strings.Join(lines, "\n"), "\n"))
+}
+
+// withNewline returns s ending with a newline unless s is empty.
+func withNewline(s string) string {
+	if s == "" {
+		return ""
+	}
+	return s + "\n"
+}
+
+// writeYAMLList writes a list of double-quoted strings. Empty lists are
+// omitted.
+func writeYAMLList(w io.StringWriter, key string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+
+	w.WriteString(key + ":\n")
+	for _, v := range values {
+		w.WriteString("  - " + strconv.Quote(v) + "\n")
+	}
+}
@@ -0,0 +1,197 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	mts, err := ParseString(sampleExport + `TITLE: "Quoted" title
+STATUS: Draft
+TAGS: go
+-----
+BODY:
+<p>draft</p>
+-----
+--------
+`)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	var featuretests = []struct {
+		entry    *Entry
+		expected string
+	}{
+		{mts[0], `---
+title: "ポエム"
+date: 2017-04-22T20:41:58Z
+author: "catatsuy"
+slug: "poem"
+categories:
+  - "ブログ"
+  - "ポエム"
+  - "技術系"
+draft: false
+---
+<p>body</p>
+<p>extended body</p>
+`},
+		{mts[2], `---
+title: "\"Quoted\" title"
+tags:
+  - "go"
+draft: true
+---
+<p>draft</p>
+`},
+	}
+
+	for _, ft := range featuretests {
+		buf := &bytes.Buffer{}
+		err := WriteMarkdown(buf, ft.entry)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if buf.String() != ft.expected {
+			t.Errorf("Error writing, expected\n%s\ngot\n%s", ft.expected, buf.String())
+		}
+
+		// The title in the front matter reads back to TITLE
+		for _, line := range strings.Split(buf.String(), "\n") {
+			if strings.HasPrefix(line, "title: ") {
+				title, err := strconv.Unquote(strings.TrimPrefix(line, "title: "))
+				if err != nil || title != ft.entry.Title {
+					t.Errorf("title got %q, %v; want %q", title, err, ft.entry.Title)
+				}
+			}
+		}
+	}
+}
+
+func TestWriteMarkdownFiles(t *testing.T) {
+	mts, err := ParseString(sampleExport + "TITLE: no basename\n-----\n--------\n")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	dir := t.TempDir()
+	err = WriteMarkdownFiles(dir, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	for i, name := range []string{"poem.md", "2017-04-09-194939.md", "entry-3.md"} {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		buf := &bytes.Buffer{}
+		WriteMarkdown(buf, mts[i])
+		if string(b) != buf.String() {
+			t.Errorf("%s got %q; want %q", name, b, buf.String())
+		}
+	}
+
+	err = WriteMarkdownFiles(t.TempDir(), []*Entry{mts[0], mts[0]})
+	if err == nil || err.Error() != "Duplicated file name poem.md" {
+		t.Errorf("got error %v; want Duplicated file name poem.md", err)
+	}
+}
+
+func TestToFrontMatter(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `---
+title: "ポエム"
+date: 2017-04-22T20:41:58Z
+author: "catatsuy"
+slug: "poem"
+categories:
+  - "ブログ"
+  - "ポエム"
+  - "技術系"
+draft: false
+---
+`
+	if got := mts[0].ToFrontMatter(); got != expected {
+		t.Errorf("ToFrontMatter expected\n%s\ngot\n%s", expected, got)
+	}
+
+	m := NewEntry()
+	m.Title = "draft"
+	m.Status = StatusDraft
+	m.Tags = []string{"go"}
+	expected = `---
+title: "draft"
+tags:
+  - "go"
+draft: true
+---
+`
+	if got := m.ToFrontMatter(); got != expected {
+		t.Errorf("ToFrontMatter of a draft expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestToHugoContent(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := mts[0].ToFrontMatter() + "body\n\nextended body\n"
+	if got := mts[0].ToHugoContent(); got != expected {
+		t.Errorf("ToHugoContent expected\n%s\ngot\n%s", expected, got)
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	var featuretests = []struct {
+		body, extended string
+		convertBreaks  ConvertBreaks
+		expected       string
+	}{
+		{"<p>body</p>\n", "<p>extended body</p>\n", "", "body\n\nextended body\n"},
+		{
+			`<h2 class="title">Title</h2>
+<p>Read <a href="https://example.com/?a=1&amp;b=2" target="_blank">the <strong>docs</strong></a>, <em>please</em>.<br />
+Run <code>go test</code>.</p>
+<p><img alt="logo" src='/logo.png' /></p>
+<ul>
+<li>one</li>
+<li><b>two</b></li>
+</ul>
+<div>1 &lt; 2</div>
+`,
+			"",
+			"",
+			"## Title\n\nRead [the **docs**](https://example.com/?a=1&b=2), *please*.\nRun `go test`.\n\n![logo](/logo.png)\n\n- one\n- **two**\n\n1 < 2\n",
+		},
+		{"# Markdown\n\n*body*\n", "more\n", ConvertBreaksMarkdown, "# Markdown\n\n*body*\n\nmore\n"},
+		{"", "<p>extended only</p>", "", "extended only\n"},
+		{"", "", "", ""},
+	}
+
+	for _, ft := range featuretests {
+		m := NewEntry()
+		m.Body = ft.body
+		m.ExtendedBody = ft.extended
+		m.ConvertBreaks = ft.convertBreaks
+
+		if got := m.ToMarkdown(); got != ft.expected {
+			t.Errorf("ToMarkdown of %q expected\n%q\ngot\n%q", ft.body, ft.expected, got)
+		}
+	}
+}
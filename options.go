@@ -0,0 +1,323 @@
+package movabletype
+
+import (
+	"time"
+
+	"golang.org/x/text/encoding"
+)
+
+// ParseOptions configures ParseWithOptions.
+//
+// Every field uses its zero value as "use the default", so the zero
+// ParseOptions parses exactly like Parse. New options are added as fields
+// without changing the signature of ParseWithOptions.
+type ParseOptions struct {
+	// StrictConvertBreaks rejects CONVERT BREAKS values other than the
+	// ConvertBreaks constants.
+	// false (default): any value is accepted as is.
+	StrictConvertBreaks bool
+
+	// Timezone is the location in which DATE columns and the dates of
+	// comments and pings are interpreted.
+	// nil (default): UTC.
+	Timezone *time.Location
+
+	// DateLayouts are time layouts tried in order after the built-in ones
+	// for DATE columns and the dates of comments and pings.
+	// nil (default): only the layouts of Movable Type are accepted.
+	DateLayouts []string
+
+	// ISODates accepts ISO 8601 dates such as "2017-04-22T20:41:58Z",
+	// "2017-04-22T20:41:58" and "2017-04-22 20:41:58" when the layouts of
+	// Movable Type do not match. Dates without an offset are in Timezone.
+	// false (default): such dates are errors.
+	ISODates bool
+
+	// Lenient keeps STATUS values other than Draft, Publish and Future and
+	// out of range ALLOW COMMENTS / ALLOW PINGS values as is instead of
+	// returning an error.
+	// false (default): these values are errors.
+	Lenient bool
+
+	// SkipInvalid leaves single-line columns with invalid values, such as
+	// "ALLOW COMMENTS: None" or "DATE: 00/00/0000 00:00:00", at their
+	// defaults and reports them as Warning instead of returning an error.
+	// Values kept by Lenient or AllowedStatuses are not invalid.
+	// false (default): these values are errors.
+	SkipInvalid bool
+
+	// OnWarning is called with each Warning, such as the invalid values
+	// skipped by SkipInvalid. The warnings are not kept in the Parser.
+	// nil (default): warnings are kept in a Parser created by NewParser
+	// and returned by Parser.Warnings. Parse keeps no warnings.
+	OnWarning func(Warning)
+
+	// AllowedStatuses are STATUS values accepted in addition to Draft,
+	// Publish and Future.
+	// nil (default): only the three statuses are accepted.
+	AllowedStatuses []string
+
+	// ContinueOnError skips entries with invalid columns and keeps parsing.
+	// The errors are returned together as MultiError.
+	// false (default): parsing stops at the first error.
+	ContinueOnError bool
+
+	// CollectErrors keeps parsing past invalid columns, which are left at
+	// their default values, and returns the entries together with
+	// MultiError of every invalid column. Unlike ContinueOnError, the
+	// entries with invalid columns are returned as well. Errors of
+	// Validators and ValidateOnParse still skip the entry.
+	// false (default): parsing stops at the first error.
+	CollectErrors bool
+
+	// Encoding is the character encoding of the input, which is converted
+	// to UTF-8 before parsing.
+	// nil (default): the input is read as UTF-8.
+	Encoding encoding.Encoding
+
+	// Charset is the name of the character encoding of the input such as
+	// "shift_jis" or "euc-jp". It is used only when Encoding is nil.
+	// CharsetAuto detects UTF-8, UTF-16 with BOM, Shift_JIS or EUC-JP with
+	// DetectEncoding.
+	// "" (default): the input is read as UTF-8.
+	Charset string
+
+	// MaxLineSize is the maximum length of a line in bytes. A longer line
+	// is an error of the parse.
+	// 0 (default): DefaultMaxLineSize.
+	MaxLineSize int
+
+	// AutoDecompress decompresses the input when it starts with the gzip
+	// magic bytes, such as export.txt.gz. Other input is read as is.
+	// false (default): the input is not decompressed.
+	AutoDecompress bool
+
+	// OnEncodingDetected is called with the name of the encoding detected
+	// by CharsetAuto.
+	// nil (default): the detected encoding is not reported.
+	OnEncodingDetected func(name string)
+
+	// CaptureCustomFields stores columns not known by this package, such as
+	// ones added by plugins, in Entry.CustomFields.
+	// false (default): unknown columns are only kept in Entry.Extra.
+	CaptureCustomFields bool
+
+	// Validators check each parsed entry. An entry for which a validator
+	// returns an error is an error of the parse.
+	// nil (default): entries are not validated.
+	Validators []EntryValidator
+
+	// ValidateOnParse checks each parsed entry like Entry.Validate and
+	// fails the parse with its ValidationError. TITLE, DATE and STATUS are
+	// required, but AUTHOR is not. AllowedStatuses are valid STATUS values.
+	// false (default): entries are not validated.
+	ValidateOnParse bool
+
+	// SeparatorLookahead treats a "-----" line in a multi-line field as
+	// the separator only when the next line is "--------" or the start of
+	// another field such as "EXTENDED BODY:". It keeps "-----" lines of
+	// hand-edited bodies, such as horizontal rules, in the field.
+	// false (default): every "-----" line ends the field.
+	SeparatorLookahead bool
+
+	// KeepRaw keeps the text of each entry in the input in Entry.Raw.
+	// false (default): Entry.Raw is empty to save memory.
+	KeepRaw bool
+
+	// RawComment keeps COMMENT blocks as is in Entry.Comment instead of
+	// parsing them into Entry.Comments.
+	// false (default): COMMENT blocks are parsed into Entry.Comments.
+	RawComment bool
+
+	// MaxEntries stops parsing once this number of entries is returned.
+	// 0 (default): unlimited.
+	MaxEntries int
+
+	// SkipFirst skips this number of entries before returning any.
+	// Together with MaxEntries, it pages through a large export.
+	// 0 (default): no entries are skipped.
+	SkipFirst int
+}
+
+// Option changes ParseOptions. It is passed to Parse and NewParser.
+type Option func(*ParseOptions)
+
+func newParseOptions(opts []Option) ParseOptions {
+	var o ParseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithStrictConvertBreaks rejects unknown CONVERT BREAKS values.
+func WithStrictConvertBreaks() Option {
+	return func(o *ParseOptions) {
+		o.StrictConvertBreaks = true
+	}
+}
+
+// WithLocation interprets DATE columns and the dates of comments and pings
+// in loc instead of UTC, as MT exports local wall-clock times.
+func WithLocation(loc *time.Location) Option {
+	return func(o *ParseOptions) {
+		o.Timezone = loc
+	}
+}
+
+// WithDateLayouts adds time layouts tried after the built-in ones for dates
+// such as "2006-01-02 15:04:05". It can be given more than once.
+func WithDateLayouts(layouts ...string) Option {
+	return func(o *ParseOptions) {
+		o.DateLayouts = append(o.DateLayouts, layouts...)
+	}
+}
+
+// WithISODates accepts ISO 8601 dates such as "2017-04-22T20:41:58Z" and
+// "2017-04-22 20:41:58".
+func WithISODates() Option {
+	return func(o *ParseOptions) {
+		o.ISODates = true
+	}
+}
+
+// WithAllowedStatuses accepts STATUS values such as Review, Spam and
+// Unpublish written by Movable Type 6 or plugins, in addition to Draft,
+// Publish and Future. The values are stored as is. It can be given more
+// than once.
+func WithAllowedStatuses(statuses ...string) Option {
+	return func(o *ParseOptions) {
+		o.AllowedStatuses = append(o.AllowedStatuses, statuses...)
+	}
+}
+
+// WithContinueOnError skips entries with invalid columns and keeps parsing.
+func WithContinueOnError() Option {
+	return func(o *ParseOptions) {
+		o.ContinueOnError = true
+	}
+}
+
+// WithCaptureCustomFields stores unknown columns in Entry.CustomFields.
+func WithCaptureCustomFields() Option {
+	return func(o *ParseOptions) {
+		o.CaptureCustomFields = true
+	}
+}
+
+// WithValidateOnParse checks each parsed entry like Entry.Validate, without
+// requiring AUTHOR.
+func WithValidateOnParse() Option {
+	return func(o *ParseOptions) {
+		o.ValidateOnParse = true
+	}
+}
+
+// WithRawComment keeps COMMENT blocks as is in Entry.Comment.
+func WithRawComment() Option {
+	return func(o *ParseOptions) {
+		o.RawComment = true
+	}
+}
+
+// WithMaxEntries stops parsing once n entries are returned.
+func WithMaxEntries(n int) Option {
+	return func(o *ParseOptions) {
+		o.MaxEntries = n
+	}
+}
+
+// WithSkipFirst skips the first n entries.
+func WithSkipFirst(n int) Option {
+	return func(o *ParseOptions) {
+		o.SkipFirst = n
+	}
+}
+
+// WithValidator adds v to the validators of parsed entries.
+// For example, Entry.Validate can be passed to validate every entry.
+func WithValidator(v EntryValidator) Option {
+	return func(o *ParseOptions) {
+		o.Validators = append(o.Validators, v)
+	}
+}
+
+// WithEncoding sets the name of the character encoding of the input such as
+// "shift_jis" or "euc-jp", or CharsetAuto to detect it. Unknown names are
+// returned as an error of the parse.
+func WithEncoding(name string) Option {
+	return func(o *ParseOptions) {
+		o.Charset = name
+	}
+}
+
+// WithEncodingDetectedHandler sets fn to be called with the name of the
+// encoding detected by CharsetAuto.
+func WithEncodingDetectedHandler(fn func(name string)) Option {
+	return func(o *ParseOptions) {
+		o.OnEncodingDetected = fn
+	}
+}
+
+// WithAutoDecompress decompresses gzip-compressed input.
+func WithAutoDecompress() Option {
+	return func(o *ParseOptions) {
+		o.AutoDecompress = true
+	}
+}
+
+// WithMaxLineSize sets the maximum length of a line in bytes.
+func WithMaxLineSize(n int) Option {
+	return func(o *ParseOptions) {
+		o.MaxLineSize = n
+	}
+}
+
+// WithCollectErrors collects the errors of invalid columns instead of
+// stopping at the first one. See ParseOptions.CollectErrors.
+func WithCollectErrors() Option {
+	return func(o *ParseOptions) {
+		o.CollectErrors = true
+	}
+}
+
+// WithLenient keeps unknown STATUS and out of range ALLOW COMMENTS / ALLOW
+// PINGS values as is instead of returning an error. See
+// ParseOptions.Lenient.
+func WithLenient() Option {
+	return func(o *ParseOptions) {
+		o.Lenient = true
+	}
+}
+
+// WithSkipInvalid leaves columns with invalid values at their defaults and
+// reports them as Warning instead of returning an error. See
+// ParseOptions.SkipInvalid.
+func WithSkipInvalid() Option {
+	return func(o *ParseOptions) {
+		o.SkipInvalid = true
+	}
+}
+
+// WithSeparatorLookahead keeps "-----" lines inside multi-line fields. See
+// ParseOptions.SeparatorLookahead.
+func WithSeparatorLookahead() Option {
+	return func(o *ParseOptions) {
+		o.SeparatorLookahead = true
+	}
+}
+
+// WithWarningHandler sets fn to be called with each Warning as parsing
+// proceeds.
+func WithWarningHandler(fn func(w Warning)) Option {
+	return func(o *ParseOptions) {
+		o.OnWarning = fn
+	}
+}
+
+// WithKeepRaw keeps the text of each entry in Entry.Raw.
+func WithKeepRaw() Option {
+	return func(o *ParseOptions) {
+		o.KeepRaw = true
+	}
+}
@@ -0,0 +1,379 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+const sampleExport = `AUTHOR: catatsuy
+TITLE: ポエム
+BASENAME: poem
+STATUS: Publish
+ALLOW COMMENTS: 1
+ALLOW PINGS: 1
+CONVERT BREAKS: 0
+DATE: 04/22/2017 20:41:58
+PRIMARY CATEGORY: ブログ
+CATEGORY: ポエム
+CATEGORY: 技術系
+-----
+BODY:
+<p>body</p>
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+--------
+AUTHOR: catatsuy
+TITLE: 風邪で声を失った話
+BASENAME: 2017/04/09/194939
+STATUS: Publish
+ALLOW COMMENTS: 1
+CONVERT BREAKS: 0
+DATE: 04/09/2017 07:49:39 PM
+CATEGORY: 日常
+-----
+BODY:
+<p>bodybodybody</p>
+-----
+--------
+`
+
+func TestParseWithZeroOptions(t *testing.T) {
+	expected, err := Parse(bytes.NewBufferString(sampleExport))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	mts, err := ParseWithOptions(bytes.NewBufferString(sampleExport), ParseOptions{})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(mts, expected) {
+		t.Errorf("Zero ParseOptions should parse like Parse, expected %v; got %v", expected, mts)
+	}
+}
+
+func TestParseWithOptionFuncs(t *testing.T) {
+	input := sampleExport + `TITLE: custom
+STATUS: Published
+CONVERT BREAKS: wiki
+FAVORITE: 1
+-----
+COMMENT:
+AUTHOR: Foo
+comment
+-----
+--------
+`
+
+	var featuretests = []struct {
+		opt  Option
+		opts ParseOptions
+	}{
+		{WithStrictConvertBreaks(), ParseOptions{StrictConvertBreaks: true}},
+		{WithContinueOnError(), ParseOptions{ContinueOnError: true}},
+		{WithCaptureCustomFields(), ParseOptions{CaptureCustomFields: true}},
+		{WithValidateOnParse(), ParseOptions{ValidateOnParse: true}},
+		{WithRawComment(), ParseOptions{RawComment: true}},
+		{WithMaxEntries(1), ParseOptions{MaxEntries: 1}},
+		{WithSkipFirst(1), ParseOptions{SkipFirst: 1}},
+		{WithLenient(), ParseOptions{Lenient: true}},
+		{WithSkipInvalid(), ParseOptions{SkipInvalid: true}},
+		{WithCollectErrors(), ParseOptions{CollectErrors: true}},
+		{WithAllowedStatuses("Published"), ParseOptions{AllowedStatuses: []string{"Published"}}},
+	}
+
+	for i, ft := range featuretests {
+		expected, expectedErr := ParseWithOptions(strings.NewReader(input), ft.opts)
+		mts, err := Parse(strings.NewReader(input), ft.opt)
+
+		if !reflect.DeepEqual(mts, expected) || !reflect.DeepEqual(err, expectedErr) {
+			t.Errorf("option %d should parse like %+v, expected %v, %v; got %v, %v", i, ft.opts, expected, expectedErr, mts, err)
+		}
+	}
+}
+
+func TestParseLenient(t *testing.T) {
+	buf := bytes.NewBufferString("STATUS: Review\nALLOW COMMENTS: 4\nALLOW PINGS: 3\n--------\n")
+
+	mts, err := ParseWithOptions(buf, ParseOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Status != "Review" {
+		t.Errorf("m.Status got %q; want %q", mts[0].Status, "Review")
+	}
+
+	if mts[0].AllowComments != 4 {
+		t.Errorf("m.AllowComments got %d; want %d", mts[0].AllowComments, 4)
+	}
+
+	if mts[0].AllowPings != 3 {
+		t.Errorf("m.AllowPings got %d; want %d", mts[0].AllowPings, 3)
+	}
+}
+
+func TestParseSkipInvalid(t *testing.T) {
+	input := "TITLE: title\nSTATUS: Review\nALLOW COMMENTS: None\nALLOW PINGS: 3\nDATE: 00/00/0000 00:00:00\n--------\n"
+
+	var warnings []Warning
+	mts, err := ParseString(input, WithSkipInvalid(), WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	m := mts[0]
+	if m.Title != "title" || m.Status != "" || m.AllowComments != DefaultAllowComments || m.AllowPings != DefaultAllowPings || !m.Date.IsZero() {
+		t.Errorf("Invalid columns should be left at their defaults, got %q %q %d %d %v", m.Title, m.Status, m.AllowComments, m.AllowPings, m.Date)
+	}
+
+	var featuretests = []struct {
+		field, value string
+		line         int
+	}{
+		{"STATUS", "Review", 2},
+		{"ALLOW COMMENTS", "None", 3},
+		{"ALLOW PINGS", "3", 4},
+		{"DATE", "00/00/0000 00:00:00", 5},
+	}
+
+	if len(warnings) != len(featuretests) {
+		t.Fatalf("got warnings %v; want %d warnings", warnings, len(featuretests))
+	}
+
+	for i, ft := range featuretests {
+		w := warnings[i]
+		if w.Code != WarningInvalidValue || w.Field != ft.field || w.Value != ft.value || w.Line != ft.line || w.EntryIndex != 1 {
+			t.Errorf("warning %d got %+v; want %s %q at line %d", i, w, ft.field, ft.value, ft.line)
+		}
+	}
+
+	if warnings[2].String() != "line 4, entry 1: ALLOW PINGS column is allowed only 0, 1 or 2. Got 3" {
+		t.Errorf("w.String() got %q", warnings[2].String())
+	}
+
+	p := NewParser(strings.NewReader(input), WithSkipInvalid())
+	if _, err := p.All(); err != nil || len(p.Warnings()) != 4 {
+		t.Errorf("p.Warnings() got %v, %v; want 4 warnings", p.Warnings(), err)
+	}
+
+	// Values kept by Lenient and AllowedStatuses are not skipped
+	mts, err = ParseString(input, WithSkipInvalid(), WithLenient())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	m = mts[0]
+	if m.Status != "Review" || m.AllowComments != DefaultAllowComments || m.AllowPings != 3 || !m.Date.IsZero() {
+		t.Errorf("Lenient should keep STATUS and ALLOW PINGS with SkipInvalid, got %q %d %d %v", m.Status, m.AllowComments, m.AllowPings, m.Date)
+	}
+
+	mts, err = ParseString(input, WithSkipInvalid(), WithAllowedStatuses("Review"))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if mts[0].Status != "Review" {
+		t.Errorf("AllowedStatuses should keep STATUS with SkipInvalid, got %q", mts[0].Status)
+	}
+}
+
+func TestParseAllowedStatuses(t *testing.T) {
+	opts := ParseOptions{AllowedStatuses: []string{"Review"}}
+
+	mts, err := ParseWithOptions(bytes.NewBufferString("STATUS: Review\n--------\n"), opts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Status != "Review" {
+		t.Errorf("m.Status got %q; want %q", mts[0].Status, "Review")
+	}
+
+	_, err = ParseWithOptions(bytes.NewBufferString("STATUS: Spam\n--------\n"), opts)
+	if err == nil || err.Error() != "line 1, entry 1: STATUS column is allowed only Draft or Publish or Future. Got Spam" {
+		t.Errorf("Error parsing, got %q", err)
+	}
+}
+
+func TestParseWithAllowedStatuses(t *testing.T) {
+	input := "STATUS: Review\n--------\nSTATUS: Spam\n--------\nSTATUS: Unpublish\n--------\nSTATUS: draft\n--------\n"
+
+	_, err := ParseString(input)
+	if !errors.Is(err, ErrInvalidStatus) {
+		t.Errorf("got error %v; want ErrInvalidStatus by default", err)
+	}
+
+	mts, err := ParseString(input, WithAllowedStatuses("Review", "Spam"), WithAllowedStatuses("Unpublish"))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := []Status{"Review", "Spam", "Unpublish", StatusDraft}
+	for i, m := range mts {
+		if m.Status != expected[i] {
+			t.Errorf("entry %d got %q; want %q", i+1, m.Status, expected[i])
+		}
+	}
+
+	input = "TITLE: review\nSTATUS: Review\nDATE: 04/22/2017 20:41:58\n-----\n--------\n"
+	if _, err := ParseString(input, WithAllowedStatuses("Review"), WithValidateOnParse()); err != nil {
+		t.Errorf("Allowed STATUS should pass ValidateOnParse, got error %q", err)
+	}
+}
+
+func TestParseContinueOnError(t *testing.T) {
+	buf := bytes.NewBufferString(`TITLE: first
+-----
+--------
+TITLE: invalid status
+STATUS: Published
+ALLOW COMMENTS: 1
+-----
+BODY:
+body
+-----
+--------
+TITLE: second
+-----
+--------
+TITLE: invalid date
+DATE: yesterday
+-----
+--------
+TITLE: third
+-----
+--------
+`)
+
+	mts, err := ParseWithOptions(buf, ParseOptions{ContinueOnError: true})
+
+	var titles []string
+	for _, m := range mts {
+		titles = append(titles, m.Title)
+	}
+	if !reflect.DeepEqual(titles, []string{"first", "second", "third"}) {
+		t.Errorf("Valid entries should be returned, got %q", titles)
+	}
+
+	me, ok := err.(MultiError)
+	if !ok {
+		t.Fatalf("got %v; want MultiError", err)
+	}
+
+	if len(me) != 2 {
+		t.Fatalf("got %d errors; want 2", len(me))
+	}
+
+	for i, line := range []int{5, 16} {
+		pe, ok := me[i].(*ParseError)
+		if !ok || pe.Line != line {
+			t.Errorf("error %d got %v; want ParseError at line %d", i, me[i], line)
+		}
+	}
+}
+
+func TestParseMaxEntriesAndSkipFirst(t *testing.T) {
+	input := "TITLE: 1\n--------\nTITLE: 2\n--------\nTITLE: 3\n--------\nTITLE: 4\n--------\n"
+
+	var featuretests = []struct {
+		opts   ParseOptions
+		titles []string
+	}{
+		{ParseOptions{}, []string{"1", "2", "3", "4"}},
+		{ParseOptions{MaxEntries: 2}, []string{"1", "2"}},
+		{ParseOptions{SkipFirst: 3}, []string{"4"}},
+		{ParseOptions{SkipFirst: 1, MaxEntries: 2}, []string{"2", "3"}},
+		{ParseOptions{SkipFirst: 5}, nil},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := ParseWithOptions(bytes.NewBufferString(input), ft.opts)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		var titles []string
+		for _, m := range mts {
+			titles = append(titles, m.Title)
+		}
+
+		if !reflect.DeepEqual(titles, ft.titles) {
+			t.Errorf("%+v got %q; want %q", ft.opts, titles, ft.titles)
+		}
+	}
+}
+
+func TestParseMaxEntriesStopsReading(t *testing.T) {
+	input := "TITLE: 1\n--------\nSTATUS: Published\n--------\n"
+
+	mts, err := ParseWithOptions(bytes.NewBufferString(input), ParseOptions{MaxEntries: 1})
+	if err != nil {
+		t.Fatalf("Entries after MaxEntries should not be read, got error %q", err)
+	}
+
+	if len(mts) != 1 {
+		t.Errorf("got %d entries; want 1", len(mts))
+	}
+}
+
+func TestParseCollectErrors(t *testing.T) {
+	input := `TITLE: first
+STATUS: Published
+ALLOW COMMENTS: None
+ALLOW PINGS: 1
+DATE: 00/00/0000 00:00:00
+-----
+BODY:
+body
+-----
+--------
+TITLE: second
+STATUS: Publish
+-----
+--------
+`
+
+	mts, err := ParseString(input, WithCollectErrors())
+
+	if len(mts) != 2 || mts[0].Title != "first" || mts[1].Title != "second" {
+		t.Fatalf("Entries with invalid columns should be returned, got %v", mts)
+	}
+
+	m := mts[0]
+	if m.Status != "" || m.AllowComments != DefaultAllowComments || !m.Date.IsZero() {
+		t.Errorf("Invalid columns should be left at their defaults, got %q, %d, %v", m.Status, m.AllowComments, m.Date)
+	}
+	if m.AllowPings != 1 || m.Body != "body\n" {
+		t.Errorf("Valid columns should be set, got %d, %q", m.AllowPings, m.Body)
+	}
+
+	var me MultiError
+	if !errors.As(err, &me) || len(me) != 3 {
+		t.Fatalf("got %v; want MultiError of 3 errors", err)
+	}
+
+	var featuretests = []struct {
+		field string
+		line  int
+	}{
+		{"STATUS", 2},
+		{"ALLOW COMMENTS", 3},
+		{"DATE", 5},
+	}
+
+	for i, ft := range featuretests {
+		var pe *ParseError
+		if !errors.As(me[i], &pe) || pe.Field != ft.field || pe.Line != ft.line || pe.EntryIndex != 1 {
+			t.Errorf("error %d got %v; want ParseError of %s at line %d", i, me[i], ft.field, ft.line)
+		}
+	}
+}
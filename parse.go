@@ -2,13 +2,17 @@ package movabletype
 
 import (
 	"bufio"
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"golang.org/x/text/encoding"
 )
 
 // Default
@@ -20,32 +24,131 @@ const (
 	DefaultAllowPings = -1
 )
 
+// DefaultMaxLineSize is the maximum length of a line in bytes unless
+// MaxLineSize is set. It is large enough for bodies exported without line
+// breaks.
+const DefaultMaxLineSize = 16 * 1024 * 1024
+
+// Status is a value of the STATUS column
+type Status string
+
+// STATUS values of Movable Type
+const (
+	StatusDraft   Status = "Draft"
+	StatusPublish Status = "Publish"
+	StatusFuture  Status = "Future"
+)
+
+// Valid reports whether s is Draft, Publish or Future.
+func (s Status) Valid() bool {
+	switch s {
+	case StatusDraft, StatusPublish, StatusFuture:
+		return true
+	}
+	return false
+}
+
+// ConvertBreaks is a value of the CONVERT BREAKS column
+type ConvertBreaks string
+
+// CONVERT BREAKS values emitted by Movable Type
+const (
+	ConvertBreaksNone                    ConvertBreaks = "0"
+	ConvertBreaksConvert                 ConvertBreaks = "1"
+	ConvertBreaksDefault                 ConvertBreaks = "__default__"
+	ConvertBreaksMarkdown                ConvertBreaks = "markdown"
+	ConvertBreaksMarkdownWithSmartyPants ConvertBreaks = "markdown_with_smartypants"
+	ConvertBreaksRichText                ConvertBreaks = "richtext"
+	ConvertBreaksTextile2                ConvertBreaks = "textile_2"
+)
+
+// Valid reports whether c is one of the values emitted by Movable Type.
+func (c ConvertBreaks) Valid() bool {
+	switch c {
+	case ConvertBreaksNone, ConvertBreaksConvert, ConvertBreaksDefault,
+		ConvertBreaksMarkdown, ConvertBreaksMarkdownWithSmartyPants,
+		ConvertBreaksRichText, ConvertBreaksTextile2:
+		return true
+	}
+	return false
+}
+
 // Movable Type Import Format
 type Entry struct {
-	Author   string
-	Title    string
-	Basename string
-	Status   string
+	Author   string `json:"author,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Basename string `json:"basename,omitempty"`
+	Status   Status `json:"status,omitempty"`
+
+	// UNIQUE URL of TypePad exports
+	UniqueURL string `json:"unique_url,omitempty"`
+
+	// 0 (closed), 1 (open) or 2 (closed, but existing comments are shown).
+	// If it is not inialized DefaultAllowComments.
+	AllowComments int `json:"allow_comments"`
+
+	// 0 (closed), 1 (open) or 2 (closed, but existing pings are shown).
+	// If it is not inialized DefaultAllowPings
+	AllowPings int `json:"allow_pings"`
 
-	// 0 or 1. If it is not inialized DefaultAllowComments.
-	AllowComments int
+	// Empty if it is not specified.
+	ConvertBreaks ConvertBreaks `json:"convert_breaks,omitempty"`
 
-	// 0 or 1. If it is not inialized DefaultAllowPings
-	AllowPings int
+	Date time.Time `json:"date"`
 
-	ConvertBreaks string
+	PrimaryCategory string `json:"primary_category,omitempty"`
 
-	Date time.Time
+	Category []string `json:"category,omitempty"`
 
-	PrimaryCategory string
+	Tags []string `json:"tags,omitempty"`
 
-	Category []string
+	Body string `json:"body,omitempty"`
 
-	Body string
+	ExtendedBody string `json:"extended_body,omitempty"`
 
-	ExtendedBody string
-	
-	Image string
+	Excerpt string `json:"excerpt,omitempty"`
+
+	Keywords string `json:"keywords,omitempty"`
+
+	Comments []Comment `json:"comments,omitempty"`
+
+	// Raw COMMENT blocks in the input format, set instead of Comments with
+	// RawComment. ParseComments parses it.
+	Comment string `json:"comment,omitempty"`
+
+	Pings []Ping `json:"pings,omitempty"`
+
+	// Image is the first of Images for compatibility. If it is changed to
+	// another value, Write writes it before Images.
+	Image string `json:"image,omitempty"`
+
+	// Images holds all IMAGE columns in order.
+	Images []string `json:"images,omitempty"`
+
+	// NoEntry is true for "NO ENTRY: 1", which means the block only carries
+	// comments or pings for an existing entry.
+	NoEntry bool `json:"no_entry,omitempty"`
+
+	// Columns not known by this package, captured with CaptureCustomFields
+	CustomFields map[string]string `json:"custom_fields,omitempty"`
+
+	// Columns not known by this package in the order of the input.
+	// Unlike CustomFields, it is always populated and keeps duplicated keys.
+	Extra []KeyValue `json:"extra,omitempty"`
+
+	// Raw is the text of the entry in the input without the "--------"
+	// separator, set only with KeepRaw. Line endings are normalized to LF.
+	Raw string `json:"raw,omitempty"`
+
+	// Multi-line fields not known by this package such as FOOTNOTES.
+	// Blocks with the same name are kept in the order of the input.
+	ExtraBlocks map[string][]string `json:"extra_blocks,omitempty"`
+}
+
+// KeyValue is a single-line column
+type KeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
 }
 
 // NewMT creates MT.
@@ -56,121 +159,784 @@ func NewEntry() *Entry {
 	}
 }
 
-// Parse creates MT struct from io.Reader
-func Parse(r io.Reader) ([]*Entry, error) {
-	mts := []*Entry{}
+// Parse creates MT struct from io.Reader.
+//
+// When an error occurs, the entries parsed before the failing one are
+// returned with the error. The error is ParseError whose EntryIndex is the
+// number of the failing entry, so EntryIndex-1 entries of the input are
+// completed.
+func Parse(r io.Reader, opts ...Option) ([]*Entry, error) {
+	return ParseWithOptions(r, newParseOptions(opts))
+}
+
+// ParseContext creates MT struct from io.Reader.
+// It checks ctx between entries, and when ctx is done it returns the
+// entries parsed so far with ctx.Err().
+func ParseContext(ctx context.Context, r io.Reader, opts ...Option) ([]*Entry, error) {
+	return newParser(r, newParseOptions(opts)).all(ctx)
+}
 
-	scanner := bufio.NewScanner(r)
+// ParseString creates MT struct from s
+func ParseString(s string, opts ...Option) ([]*Entry, error) {
+	return Parse(strings.NewReader(s), opts...)
+}
+
+// ParseBytes creates MT struct from b
+func ParseBytes(b []byte, opts ...Option) ([]*Entry, error) {
+	return Parse(bytes.NewReader(b), opts...)
+}
+
+// ParseWithLocation creates MT struct from io.Reader.
+// DATE columns are interpreted in loc. If loc is nil, UTC is used.
+func ParseWithLocation(r io.Reader, loc *time.Location) ([]*Entry, error) {
+	return ParseWithOptions(r, ParseOptions{Timezone: loc})
+}
+
+// ParseWithEncoding creates MT struct from io.Reader encoded in enc.
+// For example, use japanese.ShiftJIS of golang.org/x/text/encoding/japanese
+// for old exports of Japanese blogs.
+func ParseWithEncoding(r io.Reader, enc encoding.Encoding) ([]*Entry, error) {
+	return ParseWithOptions(r, ParseOptions{Encoding: enc})
+}
+
+// ParseWithOptions creates MT struct from io.Reader using opts
+func ParseWithOptions(r io.Reader, opts ParseOptions) ([]*Entry, error) {
+	return newParser(r, opts).All()
+}
+
+// Parser reads entries one by one from io.Reader.
+// It is useful for large exports which should not be loaded at once.
+type Parser struct {
+	scanner *bufio.Scanner
+	opts    ParseOptions
+	err     error
+
+	// errors of skipped entries with ContinueOnError
+	errs []error
+
+	// warnings reported without OnWarning, kept only when keepWarnings is
+	// set by NewParser as Parse does not return the Parser
+	warnings     []Warning
+	keepWarnings bool
+
+	// number of lines read so far
+	line int
+
+	// number of entries started so far
+	entry int
+
+	// current line
+	text string
+
+	// whether text is pushed back to be returned by the next scan
+	unread bool
+
+	// single-line columns set in the current entry
+	seen map[string]bool
+
+	// lines of the current entry read with KeepRaw, and the length of the
+	// last line
+	raw     []byte
+	lastRaw int
+
+	// line of EXTENDED BODY in the current entry, 0 if none
+	extendedLine int
+
+	// number of entries skipped by SkipFirst and returned so far
+	skipped  int
+	returned int
+}
+
+// NewParser creates Parser reading from r.
+func NewParser(r io.Reader, opts ...Option) *Parser {
+	p := newParser(r, newParseOptions(opts))
+	p.keepWarnings = true
+	return p
+}
+
+// Decoder is an alias of Parser named after encoding/json.Decoder.
+type Decoder = Parser
+
+// NewDecoder creates Decoder reading from r.
+func NewDecoder(r io.Reader, opts ...Option) *Decoder {
+	return NewParser(r, opts...)
+}
+
+// Errors returns errors of the entries skipped so far with ContinueOnError.
+func (p *Parser) Errors() []error {
+	return p.errs
+}
+
+func newParser(r io.Reader, opts ParseOptions) *Parser {
+	p := &Parser{opts: opts}
 
 	var err error
+	if opts.AutoDecompress {
+		r, err = decompressInput(r)
+	}
+	if err == nil {
+		r, err = decodeInput(r, opts)
+	}
+	if err != nil {
+		p.err = err
+	}
 
+	p.scanner = bufio.NewScanner(r)
+	p.scanner.Buffer(nil, p.maxLineSize())
+
+	return p
+}
+
+// Next returns the next entry. It returns io.EOF when no entries remain.
+func (p *Parser) Next() (*Entry, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	if p.opts.MaxEntries > 0 && p.returned >= p.opts.MaxEntries {
+		p.err = io.EOF
+		return nil, p.err
+	}
+
+	for {
+		m, err := p.next()
+		if err != nil {
+			p.err = err
+			return nil, err
+		}
+
+		if p.skipped < p.opts.SkipFirst {
+			p.skipped++
+			continue
+		}
+
+		p.returned++
+		return m, nil
+	}
+}
+
+// All returns all remaining entries.
+//
+// When an error occurs, the entries before the failing one are returned
+// with the error. The EntryIndex of ParseError tells the failing entry.
+// With ContinueOnError, entries without errors are returned together with
+// MultiError of the skipped entries.
+func (p *Parser) All() ([]*Entry, error) {
+	return p.all(context.Background())
+}
+
+// all returns all remaining entries. When ctx is done, it stops at an
+// entry boundary and returns the entries parsed so far with ctx.Err().
+func (p *Parser) all(ctx context.Context) ([]*Entry, error) {
+	mts := []*Entry{}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return mts, err
+		}
+
+		m, err := p.Next()
+		if err == io.EOF {
+			if len(p.errs) > 0 {
+				return mts, MultiError(p.errs)
+			}
+			return mts, nil
+		}
+		if err != nil {
+			return mts, err
+		}
+
+		mts = append(mts, m)
+	}
+}
+
+func (p *Parser) next() (*Entry, error) {
 	m := NewEntry()
+	p.startEntry()
+
+	// whether m has an invalid column and is skipped by ContinueOnError
+	skip := false
+
+	// whether the single-line columns of m have ended with "-----"
+	blocks := false
+
+	for p.scan() {
+		ss := strings.SplitN(p.text, ": ", 2)
 
-	for scanner.Scan() {
-		ss := strings.Split(scanner.Text(), ": ")
+		var err error
 
 		if len(ss) <= 1 {
 			value := ss[0]
 
 			if value == "--------" {
-				mts = append(mts, m)
+				if !skip {
+					if p.opts.KeepRaw {
+						// without the separator
+						m.Raw = string(p.raw[:len(p.raw)-p.lastRaw])
+					}
+					p.endEntry(m)
+					err = p.validate(m)
+					if err == nil {
+						return m, nil
+					}
+					if !p.opts.ContinueOnError && !p.opts.CollectErrors {
+						return nil, err
+					}
+					p.errs = append(p.errs, err)
+				}
 				m = NewEntry()
+				p.startEntry()
+				skip = false
+				blocks = false
 				continue
 			}
 
 			if value == "-----" {
+				blocks = true
 				continue
 			}
 
-			switch value {
-			case "BODY:":
-				for scanner.Scan() {
-					line := scanner.Text()
-
-					if line == "-----" {
-						break
-					}
-
-					m.Body += line + "\n"
-				}
-				break
-			case "EXTENDED BODY:":
-				for scanner.Scan() {
-					line := scanner.Text()
+			err = p.setBlock(m, value, blocks)
+		} else {
+			err = p.setField(m, ss[0], ss[1])
+			if err != nil && p.opts.SkipInvalid {
+				// The column is left at its default
+				p.warnError(err)
+				err = nil
+			}
+		}
 
-					if line == "-----" {
-						break
-					}
+		if err != nil {
+			if p.opts.CollectErrors {
+				// The column is left at its default
+				p.errs = append(p.errs, err)
+				continue
+			}
+			if !p.opts.ContinueOnError {
+				return nil, err
+			}
+			p.errs = append(p.errs, err)
+			skip = true
+		}
+	}
 
-					m.ExtendedBody += line + "\n"
-				}
-				break
+	if err := p.scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return nil, &ParseError{
+				Line:       p.line + 1,
+				EntryIndex: p.entry,
+				Reason:     fmt.Sprintf("Line is longer than %d bytes", p.maxLineSize()),
+				Err:        err,
+				read:       true,
 			}
+		}
+		return nil, &ParseError{
+			Line:       p.line + 1,
+			EntryIndex: p.entry,
+			Reason:     "Failed to read input",
+			Err:        err,
+			read:       true,
+		}
+	}
 
-			continue
+	// The last entry may not end with "--------"
+	if !skip && !m.empty() {
+		if p.opts.KeepRaw {
+			m.Raw = string(p.raw)
+		}
+		p.endEntry(m)
+		err := p.validate(m)
+		if err == nil {
+			return m, nil
 		}
+		if !p.opts.ContinueOnError && !p.opts.CollectErrors {
+			return nil, err
+		}
+		p.errs = append(p.errs, err)
+	}
 
-		key, value := ss[0], ss[1]
+	return nil, io.EOF
+}
 
-		switch key {
-		case "AUTHOR":
-			m.Author = value
-			break
-		case "TITLE":
-			m.Title = value
-			break
-		case "BASENAME":
-			m.Basename = value
-			break
-		case "STATUS":
-			if value == "Draft" || value == "Publish" || value == "Future" {
-				m.Status = value
-			} else {
-				return nil, fmt.Errorf("STATUS column is allowed only Draft or Publish or Future. Got %s", value)
-			}
-			break
-		case "ALLOW COMMENTS":
-			m.AllowComments, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, errors.Wrap(err, "ALLOW COMMENTS column is allowed only 0 or 1")
-			}
-			if m.AllowComments != 0 && m.AllowComments != 1 {
-				return nil, fmt.Errorf("ALLOW COMMENTS column is allowed only 0 or 1. Got %d", m.AllowComments)
-			}
-			break
-		case "ALLOW PINGS":
-			m.AllowPings, err = strconv.Atoi(value)
-			if err != nil {
-				return nil, errors.Wrap(err, "ALLOW PINGS column is allowed only 0 or 1")
-			}
-			if m.AllowComments != 0 && m.AllowComments != 1 {
-				return nil, fmt.Errorf("ALLOW PINGS column is allowed only 0 or 1. Got %d", m.AllowPings)
-			}
-			break
-		case "CONVERT BREAKS":
-			m.ConvertBreaks = value
-			break
-		case "DATE":
-			if strings.HasSuffix(value, "AM") || strings.HasSuffix(value, "PM") {
-				m.Date, err = time.Parse("01/02/2006 03:04:05 PM", value)
-			} else {
-				m.Date, err = time.Parse("01/02/2006 15:04:05", value)
-			}
-			if err != nil {
-				return nil, errors.Wrap(err, "Parsing error on DATE column")
-			}
-			break
-		case "PRIMARY CATEGORY":
-			m.PrimaryCategory = value
+// startEntry starts counting a new entry.
+func (p *Parser) startEntry() {
+	p.entry++
+	p.seen = map[string]bool{}
+	p.raw = p.raw[:0]
+	p.extendedLine = 0
+}
+
+// endEntry reports warnings about m as a whole when the entry ends.
+func (p *Parser) endEntry(m *Entry) {
+	if p.extendedLine > 0 && strings.TrimSpace(m.Body) == "" && strings.TrimSpace(m.ExtendedBody) != "" {
+		p.warn(Warning{Code: WarningEmptyBody, Line: p.extendedLine, EntryIndex: p.entry, Field: "BODY", Message: "empty BODY with EXTENDED BODY"})
+	}
+}
+
+// setBlock reads a multi-line field started by line and sets it to m.
+// Unknown fields are read as blocks only after the single-line columns,
+// that is when blocks is true.
+func (p *Parser) setBlock(m *Entry, line string, blocks bool) error {
+	start := p.line
+
+	switch line {
+	case "BODY:":
+		m.Body += p.scanBlock()
+		break
+	case "EXTENDED BODY:":
+		if p.extendedLine == 0 {
+			p.extendedLine = start
+		}
+		m.ExtendedBody += p.scanBlock()
+		break
+	case "EXCERPT:":
+		m.Excerpt += p.scanBlock()
+		break
+	case "KEYWORDS:":
+		m.Keywords += p.scanBlock()
+		break
+	case "COMMENT:":
+		if p.opts.RawComment {
+			m.Comment += line + "\n" + p.scanBlock() + "-----\n"
 			break
-		case "CATEGORY":
-			m.Category = append(m.Category, value)
+		}
+		c, err := parseComment(p.scanBlock(), p.location(), p.dateLayouts())
+		if err != nil {
+			return withPosition(err, start, p.entry)
+		}
+		if c.Author == "" {
+			p.warn(Warning{Code: WarningEmptyCommentAuthor, Line: start, EntryIndex: p.entry, Field: "COMMENT", Message: "COMMENT without AUTHOR"})
+		}
+		m.Comments = append(m.Comments, c)
+		break
+	case "PING:":
+		pg, err := parsePing(p.scanBlock(), p.location(), p.dateLayouts())
+		if err != nil {
+			return withPosition(err, start, p.entry)
+		}
+		m.Pings = append(m.Pings, pg)
+		break
+	default:
+		if !isBlockName(line) {
 			break
-		case "IMAGE":
+		}
+
+		name := strings.TrimSuffix(line, ":")
+
+		// "IMAGE:" is a single-line column with an empty value
+		if singleLineKeys[name] || !blocks {
+			return p.setField(m, name, "")
+		}
+
+		if m.ExtraBlocks == nil {
+			m.ExtraBlocks = map[string][]string{}
+		}
+		m.ExtraBlocks[name] = append(m.ExtraBlocks[name], p.scanBlock())
+	}
+
+	return nil
+}
+
+// isBlockName reports whether line looks like the start of a multi-line
+// field such as "IMAGE CAPTION:".
+func isBlockName(line string) bool {
+	name := strings.TrimSuffix(line, ":")
+	if name == line || name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != ' ' && r != '_' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Keys of single-line columns known by setField
+var singleLineKeys = map[string]bool{
+	"AUTHOR":           true,
+	"TITLE":            true,
+	"BASENAME":         true,
+	"UNIQUE URL":       true,
+	"STATUS":           true,
+	"ALLOW COMMENTS":   true,
+	"ALLOW PINGS":      true,
+	"NO ENTRY":         true,
+	"CONVERT BREAKS":   true,
+	"DATE":             true,
+	"PRIMARY CATEGORY": true,
+	"CATEGORY":         true,
+	"TAGS":             true,
+	"IMAGE":            true,
+}
+
+// setField sets the value of a single-line field to m.
+// An empty value of a column with validation leaves it at its default.
+func (p *Parser) setField(m *Entry, key, value string) error {
+	if value == "" {
+		switch key {
+		case "STATUS", "ALLOW COMMENTS", "ALLOW PINGS", "NO ENTRY", "DATE":
+			return nil
+		}
+	}
+
+	if singleLineKeys[key] && key != "CATEGORY" && key != "IMAGE" {
+		if p.seen[key] {
+			p.warn(Warning{Code: WarningDuplicateField, Line: p.line, EntryIndex: p.entry, Field: key, Value: value, Message: fmt.Sprintf("Duplicated %s column", key)})
+		}
+		p.seen[key] = true
+	}
+
+	switch key {
+	case "AUTHOR":
+		m.Author = value
+		break
+	case "TITLE":
+		m.Title = value
+		break
+	case "BASENAME":
+		m.Basename = value
+		break
+	case "UNIQUE URL":
+		m.UniqueURL = value
+		break
+	case "STATUS":
+		status, ok := p.status(value)
+		if !ok {
+			return p.errorf(key, value, nil, "STATUS column is allowed only Draft or Publish or Future. Got %s", value)
+		}
+		m.Status = status
+		break
+	case "ALLOW COMMENTS":
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return p.errorf(key, value, err, "ALLOW COMMENTS column is allowed only 0, 1 or 2")
+		}
+		if !validAllow(v) && !p.opts.Lenient {
+			return p.errorf(key, value, nil, "ALLOW COMMENTS column is allowed only 0, 1 or 2. Got %d", v)
+		}
+		m.AllowComments = v
+		break
+	case "ALLOW PINGS":
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return p.errorf(key, value, err, "ALLOW PINGS column is allowed only 0, 1 or 2")
+		}
+		if !validAllow(v) && !p.opts.Lenient {
+			return p.errorf(key, value, nil, "ALLOW PINGS column is allowed only 0, 1 or 2. Got %d", v)
+		}
+		m.AllowPings = v
+		break
+	case "CONVERT BREAKS":
+		if p.opts.StrictConvertBreaks && !ConvertBreaks(value).Valid() {
+			return p.errorf(key, value, nil, "CONVERT BREAKS column is allowed only 0, 1, __default__, markdown, markdown_with_smartypants, richtext or textile_2. Got %s", value)
+		}
+		m.ConvertBreaks = ConvertBreaks(value)
+		break
+	case "DATE":
+		d, err := parseDate(value, p.location(), p.dateLayouts())
+		if err != nil {
+			return p.errorf(key, value, err, "Parsing error on DATE column")
+		}
+		if isFallbackDate(value, d) {
+			p.warn(Warning{Code: WarningDateFallback, Line: p.line, EntryIndex: p.entry, Field: key, Value: value, Message: fmt.Sprintf("DATE column is not in the format %s", dateLayouts(value)[0])})
+		}
+		m.Date = d
+		break
+	case "PRIMARY CATEGORY":
+		m.PrimaryCategory = value
+		break
+	case "CATEGORY":
+		m.Category = append(m.Category, value)
+		break
+	case "NO ENTRY":
+		switch value {
+		case "0":
+			m.NoEntry = false
+		case "1":
+			m.NoEntry = true
+		default:
+			return p.errorf(key, value, nil, "NO ENTRY column is allowed only 0 or 1. Got %s", value)
+		}
+		break
+	case "TAGS":
+		m.Tags = parseTags(value)
+		break
+	case "IMAGE":
+		if value != "" {
+			m.Images = append(m.Images, value)
+		}
+		if m.Image == "" {
 			m.Image = value
-			break
 		}
+		break
+	default:
+		p.warn(Warning{Code: WarningUnknownKey, Line: p.line, EntryIndex: p.entry, Field: key, Value: value, Message: fmt.Sprintf("Unknown column %s", key)})
+		m.Extra = append(m.Extra, KeyValue{Key: key, Value: value})
+		if p.opts.CaptureCustomFields {
+			m.SetCustomField(key, value)
+		}
+	}
+
+	return nil
+}
+
+// ParseFile creates MT struct from the file at path
+func ParseFile(path string) ([]*Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open %s", path)
+	}
+	defer f.Close()
+
+	mts, err := Parse(f)
+	if err != nil {
+		return mts, errors.Wrapf(err, "Failed to parse %s", path)
 	}
 
 	return mts, nil
 }
+
+// validate checks m with Entry.Validate and the validators of the options.
+func (p *Parser) validate(m *Entry) error {
+	if p.opts.ValidateOnParse {
+		if err := m.validate(false, p.opts.AllowedStatuses); err != nil {
+			return p.errorf("", "", err, "Validation error")
+		}
+	}
+
+	for _, v := range p.opts.Validators {
+		if err := v(m); err != nil {
+			return p.errorf("", "", err, "Validation error")
+		}
+	}
+	return nil
+}
+
+// errorf creates ParseError of field with value at the current line and
+// entry.
+func (p *Parser) errorf(field, value string, err error, format string, args ...interface{}) error {
+	return &ParseError{
+		Line:       p.line,
+		EntryIndex: p.entry,
+		Field:      field,
+		Value:      value,
+		Reason:     fmt.Sprintf(format, args...),
+		Err:        err,
+	}
+}
+
+// validAllow reports whether v is allowed in the ALLOW COMMENTS and
+// ALLOW PINGS columns.
+func validAllow(v int) bool {
+	return 0 <= v && v <= 2
+}
+
+// status returns the Status of the value of STATUS. Draft, Publish and
+// Future are matched case-insensitively and returned in their canonical
+// capitalization. AllowedStatuses, and any value with Lenient, are
+// returned as is.
+func (p *Parser) status(value string) (Status, bool) {
+	for _, s := range []Status{StatusDraft, StatusPublish, StatusFuture} {
+		if strings.EqualFold(value, string(s)) {
+			return s, true
+		}
+	}
+
+	if p.opts.Lenient || containsString(p.opts.AllowedStatuses, value) {
+		return Status(value), true
+	}
+
+	return "", false
+}
+
+// dateLayouts returns the layouts tried after the built-in ones: the ISO
+// 8601 layouts with ISODates and then DateLayouts.
+func (p *Parser) dateLayouts() []string {
+	if !p.opts.ISODates {
+		return p.opts.DateLayouts
+	}
+	return append(append([]string{}, isoDateLayouts...), p.opts.DateLayouts...)
+}
+
+// location returns the location in which DATE columns are interpreted.
+func (p *Parser) location() *time.Location {
+	if p.opts.Timezone == nil {
+		return time.UTC
+	}
+	return p.opts.Timezone
+}
+
+// Layouts of DATE columns with AM or PM, tried in order. 12 AM is
+// midnight and 12 PM is noon. Hour 00, which some converters write, reads
+// as 12, so 00:41:58 PM is 12:41:58 and 00:41:58 AM is 00:41:58. It is
+// reported with WarningDateFallback. Hours above 12 are errors.
+var dateLayouts12 = []string{
+	"01/02/2006 03:04:05 PM",
+	"1/2/2006 3:04:05 PM",
+	"1/2/2006 3:04 PM",
+}
+
+// Layouts of DATE columns in 24-hour clock, tried in order
+var dateLayouts24 = []string{
+	"01/02/2006 15:04:05",
+	"1/2/2006 15:04:05",
+	"1/2/2006 15:04",
+}
+
+// Layouts of ISO 8601 dates tried with ISODates
+var isoDateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// Layouts of DATE columns followed by a UTC offset such as +0900, written
+// by some third-party exporters
+var (
+	dateLayouts12Offset = withDateOffset(dateLayouts12)
+	dateLayouts24Offset = withDateOffset(dateLayouts24)
+)
+
+func withDateOffset(layouts []string) []string {
+	ls := make([]string, 0, len(layouts))
+	for _, l := range layouts {
+		ls = append(ls, l+" -0700")
+	}
+	return ls
+}
+
+// dateLayouts returns the layouts to parse value, the first of which is the
+// layout written by Movable Type.
+func dateLayouts(value string) []string {
+	if hasDateOffset(value) {
+		value = strings.TrimSpace(value[:len(value)-len("-0700")])
+		if strings.HasSuffix(value, "AM") || strings.HasSuffix(value, "PM") {
+			return dateLayouts12Offset
+		}
+		return dateLayouts24Offset
+	}
+
+	if strings.HasSuffix(value, "AM") || strings.HasSuffix(value, "PM") {
+		return dateLayouts12
+	}
+	return dateLayouts24
+}
+
+// hasDateOffset reports whether value ends with a UTC offset of ±HHMM.
+func hasDateOffset(value string) bool {
+	if len(value) < len(" -0700") {
+		return false
+	}
+
+	offset := value[len(value)-len("-0700"):]
+	if offset[0] != '+' && offset[0] != '-' || value[len(value)-len(" -0700")] != ' ' {
+		return false
+	}
+	for _, c := range offset[1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// isFallbackDate reports whether value parsed into t is not in the layout
+// written by Movable Type, such as "4/9/2017 19:49".
+func isFallbackDate(value string, t time.Time) bool {
+	return t.Format(dateLayouts(value)[0]) != value
+}
+
+// parseDate parses the value of DATE columns in loc with the built-in
+// layouts followed by extra. If no layout matches, the error lists the
+// layouts tried and wraps the error of the first one.
+func parseDate(value string, loc *time.Location, extra []string) (time.Time, error) {
+	layouts := append(append([]string{}, dateLayouts(value)...), extra...)
+
+	var firstErr error
+	for _, layout := range layouts {
+		t, err := time.ParseInLocation(layout, value, loc)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return time.Time{}, errors.Wrapf(firstErr, "No layout matched in %q", layouts)
+}
+
+// maxLineSize returns the maximum length of a line in bytes.
+func (p *Parser) maxLineSize() int {
+	if p.opts.MaxLineSize > 0 {
+		return p.opts.MaxLineSize
+	}
+	return DefaultMaxLineSize
+}
+
+// scan advances to the next line.
+func (p *Parser) scan() bool {
+	if p.unread {
+		p.unread = false
+		return true
+	}
+
+	// bufio.Scanner returns the rest of a too long line by the next Scan
+	if p.scanner.Err() != nil || !p.scanner.Scan() {
+		return false
+	}
+
+	p.line++
+	// Files saved on Windows end lines with CRLF
+	p.text = strings.TrimSuffix(p.scanner.Text(), "\r")
+
+	if p.line == 1 {
+		// Files saved on Windows often start with UTF-8 BOM
+		p.text = strings.TrimPrefix(p.text, "\ufeff")
+	}
+
+	if p.opts.KeepRaw {
+		p.lastRaw = len(p.text) + 1
+		p.raw = append(append(p.raw, p.text...), '\n')
+	}
+
+	return true
+}
+
+// scanBlock reads a multi-line field until the "-----" separator.
+func (p *Parser) scanBlock() string {
+	block := ""
+
+	for p.scan() {
+		line := p.text
+
+		if line == "-----" && p.isSeparator() {
+			break
+		}
+
+		block += line + "\n"
+	}
+
+	return block
+}
+
+// isSeparator reports whether the "-----" line just read ends the block.
+// With SeparatorLookahead, it is the separator only when the next line is
+// "--------", the start of a block such as "EXTENDED BODY:", or the end of
+// the input. The next line is pushed back to be read again.
+func (p *Parser) isSeparator() bool {
+	if !p.opts.SeparatorLookahead {
+		return true
+	}
+
+	if !p.scan() {
+		return true
+	}
+	p.unread = true
+
+	return p.text == "--------" || isBlockName(p.text)
+}
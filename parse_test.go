@@ -2,8 +2,14 @@ package movabletype_test
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -57,7 +63,7 @@ EXTENDED BODY:
 			Author:          "catatsuy",
 			Title:           "ポエム",
 			Basename:        "poem",
-			Status:          "Publish",
+			Status:          StatusPublish,
 			AllowComments:   1,
 			AllowPings:      1,
 			ConvertBreaks:   "0",
@@ -71,7 +77,7 @@ EXTENDED BODY:
 			Author:        "catatsuy",
 			Title:         "風邪で声を失った話",
 			Basename:      "2017/04/09/194939",
-			Status:        "Publish",
+			Status:        StatusPublish,
 			AllowComments: 1,
 			AllowPings:    -1,
 			ConvertBreaks: "0",
@@ -89,7 +95,7 @@ EXTENDED BODY:
 	}
 
 	if !reflect.DeepEqual(mts, expected) {
-		t.Errorf("Error parsing, expected %q; got %q", expected, mts)
+		t.Errorf("Error parsing, expected %v; got %v", expected, mts)
 	}
 }
 
@@ -98,11 +104,59 @@ func TestParseStatusNotAllowed(t *testing.T) {
 
 	_, err := Parse(buf)
 
-	if err == nil || err.Error() != "STATUS column is allowed only Draft or Publish or Future. Got Published" {
+	if err == nil || err.Error() != "line 1, entry 1: STATUS column is allowed only Draft or Publish or Future. Got Published" {
 		t.Errorf("Error parsing, got %q", err)
 	}
 }
 
+func TestParseStatusCaseInsensitive(t *testing.T) {
+	var featuretests = []struct {
+		value    string
+		expected Status
+	}{
+		{"publish", StatusPublish},
+		{"DRAFT", StatusDraft},
+		{"fUTURE", StatusFuture},
+		{"Publish", StatusPublish},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := ParseString("STATUS: " + ft.value + "\n-----\n--------\n")
+		if err != nil {
+			t.Fatalf("%q got error %q", ft.value, err)
+		}
+		if mts[0].Status != ft.expected {
+			t.Errorf("%q got %q; want %q", ft.value, mts[0].Status, ft.expected)
+		}
+	}
+
+	for _, value := range []string{"published", "PUBLISHED"} {
+		_, err := ParseString("STATUS: " + value + "\n-----\n--------\n")
+		if !errors.Is(err, ErrInvalidStatus) {
+			t.Errorf("%q got error %v; want ErrInvalidStatus", value, err)
+		}
+	}
+}
+
+func TestStatusValid(t *testing.T) {
+	var featuretests = []struct {
+		status Status
+		valid  bool
+	}{
+		{StatusDraft, true},
+		{StatusPublish, true},
+		{StatusFuture, true},
+		{"Published", false},
+		{"", false},
+	}
+
+	for _, ft := range featuretests {
+		if ft.status.Valid() != ft.valid {
+			t.Errorf("Status(%q).Valid() got %v; want %v", ft.status, !ft.valid, ft.valid)
+		}
+	}
+}
+
 func TestParseDate(t *testing.T) {
 	var featuretests = []struct {
 		buf io.Reader
@@ -120,6 +174,30 @@ func TestParseDate(t *testing.T) {
 			bytes.NewBufferString("DATE: 04/22/2017 20:41:58\n--------\n"),
 			time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC),
 		},
+		{
+			bytes.NewBufferString("DATE: 4/9/2017 7:49 PM\n--------\n"),
+			time.Date(2017, time.April, 9, 19, 49, 0, 0, time.UTC),
+		},
+		{
+			bytes.NewBufferString("DATE: 04/09/2017 07:49 PM\n--------\n"),
+			time.Date(2017, time.April, 9, 19, 49, 0, 0, time.UTC),
+		},
+		{
+			bytes.NewBufferString("DATE: 4/9/2017 7:49:39 AM\n--------\n"),
+			time.Date(2017, time.April, 9, 7, 49, 39, 0, time.UTC),
+		},
+		{
+			bytes.NewBufferString("DATE: 4/9/2017 19:49:39\n--------\n"),
+			time.Date(2017, time.April, 9, 19, 49, 39, 0, time.UTC),
+		},
+		{
+			bytes.NewBufferString("DATE: 04/22/2017 20:41\n--------\n"),
+			time.Date(2017, time.April, 22, 20, 41, 0, 0, time.UTC),
+		},
+		{
+			bytes.NewBufferString("DATE: 04/22/2017 08:41 PM\n--------\n"),
+			time.Date(2017, time.April, 22, 20, 41, 0, 0, time.UTC),
+		},
 	}
 
 	for _, ft := range featuretests {
@@ -135,6 +213,163 @@ func TestParseDate(t *testing.T) {
 	}
 }
 
+func TestParseDate12Hour(t *testing.T) {
+	var featuretests = []struct {
+		value    string
+		t        time.Time
+		fallback bool
+	}{
+		{"04/22/2017 12:00:00 AM", time.Date(2017, time.April, 22, 0, 0, 0, 0, time.UTC), false},
+		{"04/22/2017 12:41:58 AM", time.Date(2017, time.April, 22, 0, 41, 58, 0, time.UTC), false},
+		{"04/22/2017 01:00:00 AM", time.Date(2017, time.April, 22, 1, 0, 0, 0, time.UTC), false},
+		{"04/22/2017 11:59:59 AM", time.Date(2017, time.April, 22, 11, 59, 59, 0, time.UTC), false},
+		{"04/22/2017 12:00:00 PM", time.Date(2017, time.April, 22, 12, 0, 0, 0, time.UTC), false},
+		{"04/22/2017 12:41:58 PM", time.Date(2017, time.April, 22, 12, 41, 58, 0, time.UTC), false},
+		{"04/22/2017 01:00:00 PM", time.Date(2017, time.April, 22, 13, 0, 0, 0, time.UTC), false},
+		{"04/22/2017 11:59:59 PM", time.Date(2017, time.April, 22, 23, 59, 59, 0, time.UTC), false},
+		{"04/22/2017 00:41:58 AM", time.Date(2017, time.April, 22, 0, 41, 58, 0, time.UTC), true},
+		{"04/22/2017 00:41:58 PM", time.Date(2017, time.April, 22, 12, 41, 58, 0, time.UTC), true},
+		{"4/22/2017 0:41 PM", time.Date(2017, time.April, 22, 12, 41, 0, 0, time.UTC), true},
+		{"4/22/2017 12:41 AM", time.Date(2017, time.April, 22, 0, 41, 0, 0, time.UTC), true},
+	}
+
+	for _, ft := range featuretests {
+		p := NewParser(strings.NewReader("DATE: " + ft.value + "\n-----\n--------\n"))
+		mts, err := p.All()
+		if err != nil {
+			t.Fatalf("%q got error %q", ft.value, err)
+		}
+
+		if !mts[0].Date.Equal(ft.t) {
+			t.Errorf("%q got %v; want %v", ft.value, mts[0].Date, ft.t)
+		}
+
+		fallback := len(p.Warnings()) > 0 && p.Warnings()[0].Code == WarningDateFallback
+		if fallback != ft.fallback {
+			t.Errorf("%q got fallback %v; want %v", ft.value, fallback, ft.fallback)
+		}
+	}
+
+	for _, value := range []string{"04/22/2017 13:00:00 PM", "04/22/2017 24:00:00 AM"} {
+		_, err := ParseString("DATE: " + value + "\n-----\n--------\n")
+		if !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("%q got error %v; want ErrInvalidDate", value, err)
+		}
+	}
+}
+
+func TestParseDateWithOffset(t *testing.T) {
+	jst := time.FixedZone("", 9*60*60)
+	pst := time.FixedZone("", -8*60*60)
+
+	var featuretests = []struct {
+		value string
+		t     time.Time
+	}{
+		{"04/22/2017 20:41:58 +0900", time.Date(2017, time.April, 22, 20, 41, 58, 0, jst)},
+		{"04/22/2017 08:41:58 PM +0900", time.Date(2017, time.April, 22, 20, 41, 58, 0, jst)},
+		{"4/22/2017 8:41 PM -0800", time.Date(2017, time.April, 22, 20, 41, 0, 0, pst)},
+		{"4/22/2017 20:41 +0000", time.Date(2017, time.April, 22, 20, 41, 0, 0, time.UTC)},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := ParseString("DATE: " + ft.value + "\n-----\n--------\n")
+		if err != nil {
+			t.Fatalf("%q got error %q", ft.value, err)
+		}
+
+		if !mts[0].Date.Equal(ft.t) {
+			t.Errorf("%q got %v; want %v", ft.value, mts[0].Date, ft.t)
+		}
+		_, offset := mts[0].Date.Zone()
+		_, expected := ft.t.Zone()
+		if offset != expected {
+			t.Errorf("%q got offset %d; want %d", ft.value, offset, expected)
+		}
+	}
+
+	for _, value := range []string{"04/22/2017 20:41:58 +09", "04/22/2017 20:41:58+0900", "04/22/2017 20:41:58 +09:00"} {
+		_, err := ParseString("DATE: " + value + "\n-----\n--------\n")
+		if err == nil {
+			t.Errorf("%q should be an error", value)
+		}
+	}
+}
+
+func TestParseInvalidDate(t *testing.T) {
+	for _, value := range []string{"2023-01-02 15:30:45", "2017-04-22T20:41:58Z", "00/00/0000 00:00:00"} {
+		_, err := ParseString("DATE: " + value + "\n-----\n--------\n")
+		if !errors.Is(err, ErrInvalidDate) {
+			t.Errorf("%q got error %v; want ErrInvalidDate", value, err)
+		}
+	}
+}
+
+func TestParseISODates(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+
+	var featuretests = []struct {
+		value string
+		t     time.Time
+	}{
+		{"2017-04-22T20:41:58Z", time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)},
+		{"2017-04-22T20:41:58+09:00", time.Date(2017, time.April, 22, 20, 41, 58, 0, jst)},
+		{"2017-04-22T20:41:58", time.Date(2017, time.April, 22, 20, 41, 58, 0, jst)},
+		{"2023-01-02 15:30:45", time.Date(2023, time.January, 2, 15, 30, 45, 0, jst)},
+		{"2023-01-02 15:30", time.Date(2023, time.January, 2, 15, 30, 0, 0, jst)},
+		{"04/22/2017 20:41:58", time.Date(2017, time.April, 22, 20, 41, 58, 0, jst)},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := ParseString("DATE: "+ft.value+"\n-----\n--------\n", WithISODates(), WithLocation(jst))
+		if err != nil {
+			t.Fatalf("%q got error %q", ft.value, err)
+		}
+		if !mts[0].Date.Equal(ft.t) {
+			t.Errorf("%q got %v; want %v", ft.value, mts[0].Date, ft.t)
+		}
+	}
+
+	_, err := ParseString("DATE: 2017/04/22\n-----\n--------\n", WithISODates())
+	if !errors.Is(err, ErrInvalidDate) {
+		t.Errorf("got error %v; want ErrInvalidDate", err)
+	}
+}
+
+func TestParseDateWithoutSeconds(t *testing.T) {
+	var featuretests = []struct {
+		input    string
+		t        time.Time
+		fallback bool
+	}{
+		{"DATE: 04/22/2017 20:41\n--------\n", time.Date(2017, time.April, 22, 20, 41, 0, 0, time.UTC), true},
+		{"DATE: 04/22/2017 20:41:58\n--------\n", time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC), false},
+		{"DATE: 04/22/2017 08:41:58 PM\n--------\n", time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC), false},
+	}
+
+	for _, ft := range featuretests {
+		p := NewParser(strings.NewReader(ft.input))
+		mts, err := p.All()
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if !mts[0].Date.Equal(ft.t) {
+			t.Errorf("%q got %v; want %v", ft.input, mts[0].Date, ft.t)
+		}
+
+		fallback := false
+		for _, w := range p.Warnings() {
+			if w.Code == WarningDateFallback {
+				fallback = true
+			}
+		}
+		if fallback != ft.fallback {
+			t.Errorf("%q got fallback %v; want %v", ft.input, fallback, ft.fallback)
+		}
+	}
+}
+
 func TestNewMT(t *testing.T) {
 	m := NewEntry()
 
@@ -146,3 +381,770 @@ func TestNewMT(t *testing.T) {
 		t.Errorf("By default, AllowComments is %d, got %d", DefaultAllowPings, m.AllowPings)
 	}
 }
+
+func TestParseConvertBreaks(t *testing.T) {
+	for _, v := range []string{"0", "1", "markdown", "richtext", "textile_2"} {
+		input := "CONVERT BREAKS: " + v + "\n-----\n--------\n"
+
+		mts, err := Parse(bytes.NewBufferString(input))
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if mts[0].ConvertBreaks != ConvertBreaks(v) {
+			t.Errorf("m.ConvertBreaks got %q; want %q", mts[0].ConvertBreaks, v)
+		}
+
+		buf := &bytes.Buffer{}
+		err = Write(buf, mts)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if buf.String() != input {
+			t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+		}
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "export.txt")
+	err := os.WriteFile(path, []byte("TITLE: title\n-----\n--------\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mts, err := ParseFile(path)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if len(mts) != 1 || mts[0].Title != "title" {
+		t.Errorf("Error parsing file, got %v", mts)
+	}
+}
+
+func TestParseFileError(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := ParseFile(filepath.Join(dir, "missing.txt"))
+	if err == nil || !strings.Contains(err.Error(), "missing.txt") {
+		t.Errorf("Error opening missing file, got %q", err)
+	}
+
+	path := filepath.Join(dir, "invalid.txt")
+	err = os.WriteFile(path, []byte("STATUS: Published\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = ParseFile(path)
+	if err == nil || !strings.Contains(err.Error(), "invalid.txt") {
+		t.Errorf("Error parsing invalid file, got %q", err)
+	}
+}
+
+func TestParseStrictConvertBreaks(t *testing.T) {
+	var featuretests = []struct {
+		value string
+		err   string
+	}{
+		{"0", ""},
+		{"1", ""},
+		{"__default__", ""},
+		{"markdown", ""},
+		{"markdown_with_smartypants", ""},
+		{"richtext", ""},
+		{"textile_2", ""},
+		{"wiki", "line 1, entry 1: CONVERT BREAKS column is allowed only 0, 1, __default__, markdown, markdown_with_smartypants, richtext or textile_2. Got wiki"},
+	}
+
+	for _, ft := range featuretests {
+		buf := bytes.NewBufferString("CONVERT BREAKS: " + ft.value + "\n--------\n")
+
+		_, err := ParseWithOptions(buf, ParseOptions{StrictConvertBreaks: true})
+
+		if ft.err == "" && err != nil {
+			t.Errorf("CONVERT BREAKS %s got error %q", ft.value, err)
+		}
+
+		if ft.err != "" && (err == nil || err.Error() != ft.err) {
+			t.Errorf("CONVERT BREAKS %s got error %q; want %q", ft.value, err, ft.err)
+		}
+	}
+
+	_, err := Parse(bytes.NewBufferString("CONVERT BREAKS: wiki\n--------\n"))
+	if err != nil {
+		t.Errorf("By default, unknown CONVERT BREAKS is accepted, got error %q", err)
+	}
+}
+
+func TestParserNext(t *testing.T) {
+	buf := bytes.NewBufferString("TITLE: first\n-----\n--------\nTITLE: second\n-----\n--------\n")
+
+	p := NewParser(buf)
+
+	for _, title := range []string{"first", "second"} {
+		m, err := p.Next()
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if m.Title != title {
+			t.Errorf("m.Title got %q; want %q", m.Title, title)
+		}
+	}
+
+	_, err := p.Next()
+	if err != io.EOF {
+		t.Errorf("After the last entry, got %v; want io.EOF", err)
+	}
+}
+
+func TestParserAll(t *testing.T) {
+	buf := bytes.NewBufferString("TITLE: first\n-----\n--------\nTITLE: second\n-----\n--------\n")
+
+	p := NewParser(buf)
+
+	m, err := p.Next()
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if m.Title != "first" {
+		t.Errorf("m.Title got %q; want %q", m.Title, "first")
+	}
+
+	mts, err := p.All()
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if len(mts) != 1 || mts[0].Title != "second" {
+		t.Errorf("All returns remaining entries, got %v", mts)
+	}
+}
+
+func TestParseWithLocation(t *testing.T) {
+	jst := time.FixedZone("Asia/Tokyo", 9*60*60)
+
+	var featuretests = []struct {
+		loc *time.Location
+		t   time.Time
+	}{
+		{nil, time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)},
+		{time.UTC, time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)},
+		{jst, time.Date(2017, time.April, 22, 20, 41, 58, 0, jst)},
+	}
+
+	for _, ft := range featuretests {
+		buf := bytes.NewBufferString("DATE: 04/22/2017 08:41:58 PM\nCOMMENT:\nDATE: 04/22/2017 20:41:58\n-----\n--------\n")
+
+		mts, err := ParseWithLocation(buf, ft.loc)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if !mts[0].Date.Equal(ft.t) || mts[0].Date.Location() != ft.t.Location() {
+			t.Errorf("m.Date got %v; want %v", mts[0].Date, ft.t)
+		}
+
+		if !mts[0].Comments[0].Date.Equal(ft.t) {
+			t.Errorf("Comment.Date got %v; want %v", mts[0].Comments[0].Date, ft.t)
+		}
+	}
+}
+
+func TestParseAllowClosedButShown(t *testing.T) {
+	mts, err := ParseString("ALLOW COMMENTS: 2\nALLOW PINGS: 2\n--------\n")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].AllowComments != 2 || mts[0].AllowPings != 2 {
+		t.Errorf("m.AllowComments, m.AllowPings got %d, %d; want 2, 2", mts[0].AllowComments, mts[0].AllowPings)
+	}
+}
+
+func TestParseAllowPingsNotAllowed(t *testing.T) {
+	buf := bytes.NewBufferString("ALLOW COMMENTS: 1\nALLOW PINGS: 3\n--------\n")
+
+	_, err := Parse(buf)
+
+	if err == nil || err.Error() != "line 2, entry 1: ALLOW PINGS column is allowed only 0, 1 or 2. Got 3" {
+		t.Errorf("Error parsing, got %q", err)
+	}
+}
+
+func TestParseNoEntry(t *testing.T) {
+	var featuretests = []struct {
+		input   string
+		noEntry bool
+	}{
+		{"NO ENTRY: 1\n-----\n--------\n", true},
+		{"NO ENTRY: 0\n-----\n--------\n", false},
+		{"TITLE: title\n-----\n--------\n", false},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := Parse(bytes.NewBufferString(ft.input))
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if mts[0].NoEntry != ft.noEntry {
+			t.Errorf("m.NoEntry got %v; want %v", mts[0].NoEntry, ft.noEntry)
+		}
+	}
+}
+
+func TestParseNoEntryNotAllowed(t *testing.T) {
+	buf := bytes.NewBufferString("NO ENTRY: yes\n--------\n")
+
+	_, err := Parse(buf)
+
+	if err == nil || err.Error() != "line 1, entry 1: NO ENTRY column is allowed only 0 or 1. Got yes" {
+		t.Errorf("Error parsing, got %q", err)
+	}
+}
+
+func TestParseUniqueURL(t *testing.T) {
+	buf := bytes.NewBufferString(`AUTHOR: typepad
+TITLE: TypePad entry
+STATUS: Publish
+UNIQUE URL: https://example.typepad.com/blog/2014/05/post.html
+DATE: 05/01/2014 10:00:00 AM
+-----
+BODY:
+<p>typepad</p>
+-----
+--------
+AUTHOR: catatsuy
+TITLE: MT entry
+BASENAME: mt
+STATUS: Publish
+DATE: 04/22/2017 20:41:58
+-----
+BODY:
+<p>mt</p>
+-----
+--------
+`)
+
+	mts, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if len(mts) != 2 {
+		t.Fatalf("got %d entries; want 2", len(mts))
+	}
+
+	if mts[0].UniqueURL != "https://example.typepad.com/blog/2014/05/post.html" {
+		t.Errorf("m.UniqueURL got %q", mts[0].UniqueURL)
+	}
+
+	if mts[0].Body != "<p>typepad</p>\n" {
+		t.Errorf("m.Body got %q", mts[0].Body)
+	}
+
+	if mts[1].UniqueURL != "" || mts[1].Basename != "mt" || mts[1].Body != "<p>mt</p>\n" {
+		t.Errorf("MT entry got %v", mts[1])
+	}
+}
+
+func TestParseBOM(t *testing.T) {
+	buf := bytes.NewBufferString("\xef\xbb\xbfAUTHOR: catatsuy\nTITLE: title\n-----\n--------\n")
+
+	mts, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Author != "catatsuy" {
+		t.Errorf("m.Author got %q; want %q", mts[0].Author, "catatsuy")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	buf := bytes.NewBufferString("TITLE: first\n-----\n--------\nTITLE: second\n-----\n--------\n")
+
+	dec := NewDecoder(buf)
+
+	var titles []string
+	for {
+		m, err := dec.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+		titles = append(titles, m.Title)
+	}
+
+	if !reflect.DeepEqual(titles, []string{"first", "second"}) {
+		t.Errorf("Titles got %q", titles)
+	}
+}
+
+func TestParseStringAndBytes(t *testing.T) {
+	input := "TITLE: title\n-----\nBODY:\nbody\n-----\n--------\n"
+
+	expected, err := Parse(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !reflect.DeepEqual(mts, expected) {
+		t.Errorf("ParseString expected %v; got %v", expected, mts)
+	}
+
+	mts, err = ParseBytes([]byte(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !reflect.DeepEqual(mts, expected) {
+		t.Errorf("ParseBytes expected %v; got %v", expected, mts)
+	}
+}
+
+func TestParseEmptyInput(t *testing.T) {
+	for _, input := range []string{"", "\n", "-----\n"} {
+		mts, err := ParseString(input)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if mts == nil || len(mts) != 0 {
+			t.Errorf("Empty input %q should be an empty slice, got %v", input, mts)
+		}
+	}
+}
+
+func TestParseWithoutLastSeparator(t *testing.T) {
+	input := "TITLE: first\n-----\nBODY:\nbody\n-----\n--------\nTITLE: second\n-----\nBODY:\nlast body\n-----\n"
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if len(mts) != 2 {
+		t.Fatalf("got %d entries; want 2", len(mts))
+	}
+
+	if mts[1].Title != "second" || mts[1].Body != "last body\n" {
+		t.Errorf("Last entry got %v", mts[1])
+	}
+
+	var featuretests = []struct {
+		input string
+		count int
+	}{
+		{"TITLE: first\n-----\n--------\nAUTHOR: foo\n", 2},
+		{"TITLE: first\n-----\n--------\nAUTHOR: foo\n-----\nBODY:\nbody", 2},
+		{"TITLE: first\n-----\n--------\n\n", 1},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := ParseString(ft.input)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+		if len(mts) != ft.count {
+			t.Errorf("%q got %d entries; want %d", ft.input, len(mts), ft.count)
+		}
+	}
+}
+
+func TestParseEmptyValue(t *testing.T) {
+	var featuretests = []struct {
+		input string
+	}{
+		{"TITLE: title\nIMAGE:\nBODY:\nbody\n-----\n--------\n"},
+		{"TITLE: title\n-----\nIMAGE:\nBODY:\nbody\n-----\n--------\n"},
+		{"TITLE: title\nBASENAME:\nSTATUS:\nDATE:\nIMAGE:\n-----\nBODY:\nbody\n-----\n--------\n"},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := ParseString(ft.input)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if len(mts) != 1 {
+			t.Fatalf("got %d entries; want 1", len(mts))
+		}
+
+		if mts[0].Title != "title" || mts[0].Image != "" || mts[0].Body != "body\n" {
+			t.Errorf("Error parsing %q, got %v", ft.input, mts[0])
+		}
+
+		if mts[0].ExtraBlocks != nil {
+			t.Errorf("IMAGE: should not be a block, got %v", mts[0].ExtraBlocks)
+		}
+	}
+}
+
+func TestParseEmptyUnknownValue(t *testing.T) {
+	mts, err := ParseString("TITLE: title\nFAVORITE:\n-----\n--------\n")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := []KeyValue{{Key: "FAVORITE", Value: ""}}
+	if !reflect.DeepEqual(mts[0].Extra, expected) {
+		t.Errorf("m.Extra got %v; want %v", mts[0].Extra, expected)
+	}
+}
+
+func TestParseContext(t *testing.T) {
+	input := "TITLE: first\n-----\n--------\nTITLE: second\n-----\n--------\n"
+
+	mts, err := ParseContext(context.Background(), strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if len(mts) != 2 {
+		t.Errorf("got %d entries; want 2", len(mts))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	mts, err = ParseContext(ctx, strings.NewReader(input))
+	if err != context.DeadlineExceeded {
+		t.Errorf("got error %v; want %v", err, context.DeadlineExceeded)
+	}
+
+	if mts == nil || len(mts) != 0 {
+		t.Errorf("Entries parsed before the deadline should be returned, got %v", mts)
+	}
+}
+
+func TestParseContextCanceled(t *testing.T) {
+	input := "TITLE: first\n-----\n--------\nTITLE: second\n-----\n--------\nTITLE: third\n-----\n--------\n"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// cancel while the first entry is parsed
+	cancelOnFirst := func(e *Entry) error {
+		if e.Title == "first" {
+			cancel()
+		}
+		return nil
+	}
+
+	mts, err := ParseContext(ctx, strings.NewReader(input), WithValidator(cancelOnFirst))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v; want %v", err, context.Canceled)
+	}
+
+	if len(mts) != 1 || mts[0].Title != "first" {
+		t.Errorf("Parsing should stop after the first entry, got %v", mts)
+	}
+}
+
+func TestParseInTokyo(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("Asia/Tokyo is not available: %v", err)
+	}
+
+	input := "DATE: 04/22/2017 20:41:58\n-----\n--------\n"
+
+	utc, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	jst, err := ParseWithOptions(strings.NewReader(input), ParseOptions{Timezone: tokyo})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if d := utc[0].Date.Sub(jst[0].Date); d != 9*time.Hour {
+		t.Errorf("UTC - Asia/Tokyo got %v; want %v", d, 9*time.Hour)
+	}
+
+	if jst[0].Date.Location() != tokyo {
+		t.Errorf("m.Date.Location() got %v; want %v", jst[0].Date.Location(), tokyo)
+	}
+}
+
+func TestParseWithLocationOption(t *testing.T) {
+	input := `DATE: 04/22/2017 20:41:58
+-----
+COMMENT:
+AUTHOR: foo
+DATE: 04/23/2017 08:00:00
+comment
+-----
+--------
+`
+
+	mts, err := ParseString(input, WithLocation(time.FixedZone("JST", 9*60*60)))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if got := mts[0].Date.Format(time.RFC3339); got != "2017-04-22T20:41:58+09:00" {
+		t.Errorf("m.Date got %s; want 2017-04-22T20:41:58+09:00", got)
+	}
+	if got := mts[0].Comments[0].Date.Format(time.RFC3339); got != "2017-04-23T08:00:00+09:00" {
+		t.Errorf("comment date got %s; want 2017-04-23T08:00:00+09:00", got)
+	}
+
+	mts, err = ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if got := mts[0].Date.Format(time.RFC3339); got != "2017-04-22T20:41:58Z" {
+		t.Errorf("m.Date without WithLocation got %s; want 2017-04-22T20:41:58Z", got)
+	}
+}
+
+func TestParseCRLF(t *testing.T) {
+	expected, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	mts, err := ParseString(strings.Replace(sampleExport, "\n", "\r\n", -1))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(mts, expected) {
+		t.Errorf("CRLF input should parse like LF, expected %v; got %v", expected, mts)
+	}
+}
+
+func TestParseCRLFBody(t *testing.T) {
+	input := "TITLE: title\r\n-----\r\nBODY:\r\n<p>body</p>\r\n<p>bodybody</p>\r\n-----\r\n--------\r\n"
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "<p>body</p>\n<p>bodybody</p>\n"
+	if mts[0].Body != expected {
+		t.Errorf("m.Body got %q; want %q", mts[0].Body, expected)
+	}
+}
+
+func TestParseLongLine(t *testing.T) {
+	body := strings.Repeat("<p>body</p>", 1024*1024/len("<p>body</p>")+1)
+	input := "TITLE: title\n-----\nBODY:\n" + body + "\n-----\n--------\n"
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Body != body+"\n" {
+		t.Errorf("m.Body got %d bytes; want %d bytes", len(mts[0].Body), len(body)+1)
+	}
+
+	_, err = ParseString(input, WithMaxLineSize(64*1024))
+	expected := fmt.Sprintf("line 4, entry 1: Line is longer than %d bytes: bufio.Scanner: token too long", 64*1024)
+	if err == nil || err.Error() != expected {
+		t.Errorf("got error %v; want %q", err, expected)
+	}
+
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.Line != 4 {
+		t.Errorf("got error %#v; want ParseError on line 4", err)
+	}
+}
+
+func TestParseSeparatorInBody(t *testing.T) {
+	input := `TITLE: title
+-----
+BODY:
+<p>before the rule</p>
+-----
+<p>after the rule</p>
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+--------
+`
+
+	// By default, "-----" always ends the field and the rest is lost
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Body != "<p>before the rule</p>\n" {
+		t.Errorf("m.Body got %q; want %q", mts[0].Body, "<p>before the rule</p>\n")
+	}
+
+	mts, err = ParseString(input, WithSeparatorLookahead())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "<p>before the rule</p>\n-----\n<p>after the rule</p>\n"
+	if mts[0].Body != expected {
+		t.Errorf("m.Body got %q; want %q", mts[0].Body, expected)
+	}
+
+	if mts[0].ExtendedBody != "<p>extended body</p>\n" {
+		t.Errorf("m.ExtendedBody got %q; want %q", mts[0].ExtendedBody, "<p>extended body</p>\n")
+	}
+
+	expectedEntries, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	mts, err = ParseString(sampleExport, WithSeparatorLookahead())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(mts, expectedEntries) {
+		t.Errorf("Lookahead should not change well-formed input, expected %v; got %v", expectedEntries, mts)
+	}
+}
+
+func TestParseKeepRaw(t *testing.T) {
+	mts, err := ParseString(sampleExport, WithKeepRaw())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	entries := strings.SplitAfter(sampleExport, "--------\n")
+	for i, m := range mts {
+		expected := strings.TrimSuffix(entries[i], "--------\n")
+		if m.Raw != expected {
+			t.Errorf("m.Raw got %q; want %q", m.Raw, expected)
+		}
+	}
+
+	// without the last separator, and CRLF is normalized
+	mts, err = ParseString("TITLE: title\r\n-----\r\n", WithKeepRaw())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if mts[0].Raw != "TITLE: title\n-----\n" {
+		t.Errorf("m.Raw got %q; want %q", mts[0].Raw, "TITLE: title\n-----\n")
+	}
+
+	mts, err = ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if mts[0].Raw != "" {
+		t.Errorf("m.Raw should be empty by default, got %q", mts[0].Raw)
+	}
+}
+
+func TestParsePartialEntries(t *testing.T) {
+	input := sampleExport + "TITLE: broken\nSTATUS: Published\n-----\n--------\nTITLE: after\n-----\n--------\n"
+
+	mts, err := ParseString(input)
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v; want ParseError", err)
+	}
+
+	if len(mts) != 2 || mts[0].Title != "ポエム" || mts[1].Title != "風邪で声を失った話" {
+		t.Errorf("Entries before the broken one should be returned, got %v", mts)
+	}
+
+	if pe.EntryIndex-1 != len(mts) {
+		t.Errorf("pe.EntryIndex got %d; want %d", pe.EntryIndex, len(mts)+1)
+	}
+}
+
+func TestParseImages(t *testing.T) {
+	input := `TITLE: images
+IMAGE: https://example.com/1.png
+IMAGE: https://example.com/2.png
+-----
+BODY:
+body
+-----
+--------
+`
+
+	var warnings []Warning
+	mts, err := ParseString(input, WithWarningHandler(func(w Warning) { warnings = append(warnings, w) }))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := []string{"https://example.com/1.png", "https://example.com/2.png"}
+	if !reflect.DeepEqual(mts[0].Images, expected) {
+		t.Errorf("m.Images got %q; want %q", mts[0].Images, expected)
+	}
+	if mts[0].Image != expected[0] {
+		t.Errorf("m.Image got %q; want %q", mts[0].Image, expected[0])
+	}
+	if len(warnings) != 0 {
+		t.Errorf("got warnings %v", warnings)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !strings.Contains(buf.String(), "IMAGE: https://example.com/1.png\nIMAGE: https://example.com/2.png\n") {
+		t.Errorf("Write got %q", buf.String())
+	}
+
+	// A changed Image is written before Images
+	mts[0].Image = "https://example.com/0.png"
+	buf.Reset()
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !strings.Contains(buf.String(), "IMAGE: https://example.com/0.png\nIMAGE: https://example.com/1.png\nIMAGE: https://example.com/2.png\n") {
+		t.Errorf("Write got %q", buf.String())
+	}
+}
+
+func TestParseWithDateLayouts(t *testing.T) {
+	input := "DATE: 2017-04-22 20:41:58\n-----\nCOMMENT:\nDATE: 22/04/2017 20:41:58\ncomment\n-----\n--------\n"
+	expected := time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)
+
+	_, err := ParseString(input)
+	if err == nil {
+		t.Fatalf("should return an error without WithDateLayouts")
+	}
+	if !strings.Contains(err.Error(), `"01/02/2006 15:04:05"`) {
+		t.Errorf("error should list the layouts tried, got %q", err)
+	}
+
+	mts, err := ParseString(input, WithDateLayouts("2006-01-02 15:04:05"), WithDateLayouts("02/01/2006 15:04:05"))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !mts[0].Date.Equal(expected) {
+		t.Errorf("m.Date got %v; want %v", mts[0].Date, expected)
+	}
+	if !mts[0].Comments[0].Date.Equal(expected) {
+		t.Errorf("Comment.Date got %v; want %v", mts[0].Comments[0].Date, expected)
+	}
+
+	_, err = ParseString("DATE: 2017.04.22\n-----\n--------\n", WithDateLayouts("2006-01-02 15:04:05"))
+	if err == nil || !strings.Contains(err.Error(), `"2006-01-02 15:04:05"`) {
+		t.Errorf("error should list the added layouts, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package movabletype
+
+import (
+	"strings"
+	"time"
+)
+
+// Ping is a PING (TrackBack) block of Entry
+type Ping struct {
+	Title    string    `json:"title,omitempty"`
+	URL      string    `json:"url,omitempty"`
+	IP       string    `json:"ip,omitempty"`
+	BlogName string    `json:"blog_name,omitempty"`
+	Date     time.Time `json:"date"`
+
+	Body string `json:"body,omitempty"`
+
+	// Raw text of the PING block
+	Raw string `json:"raw,omitempty"`
+}
+
+// Trackback is an alias of Ping.
+type Trackback = Ping
+
+// ParsePings creates pings from PING blocks in the input format. The text
+// of a single block without the "PING:" line and the "-----" separator is
+// accepted as well. DATE is interpreted in UTC.
+func ParsePings(raw string) ([]Trackback, error) {
+	pgs := []Trackback{}
+
+	for _, block := range splitBlocks(raw, "PING:") {
+		pg, err := parsePing(block, time.UTC, nil)
+		if err != nil {
+			return nil, err
+		}
+		pgs = append(pgs, pg)
+	}
+
+	return pgs, nil
+}
+
+// parsePing creates Ping from the text of a PING block.
+// DATE is interpreted in loc, also trying layouts after the built-in ones.
+// The block starts with TITLE, URL, IP, BLOG NAME and DATE lines followed
+// by the excerpt of the pinging entry.
+func parsePing(raw string, loc *time.Location, layouts []string) (Ping, error) {
+	pg := Ping{Raw: raw}
+
+	lines := strings.SplitAfter(raw, "\n")
+
+	i := 0
+	for ; i < len(lines); i++ {
+		key, value, ok := splitBlockHeader(lines[i], "TITLE", "URL", "IP", "BLOG NAME", "DATE")
+		if !ok {
+			break
+		}
+
+		switch key {
+		case "TITLE":
+			pg.Title = value
+		case "URL":
+			pg.URL = value
+		case "IP":
+			pg.IP = value
+		case "BLOG NAME":
+			pg.BlogName = value
+		case "DATE":
+			if value == "" {
+				break
+			}
+			var err error
+			pg.Date, err = parseDate(value, loc, layouts)
+			if err != nil {
+				return pg, &ParseError{Field: "PING", Value: value, Reason: "Parsing error on DATE column of PING", Err: err}
+			}
+		}
+	}
+
+	pg.Body = strings.Join(lines[i:], "")
+
+	return pg, nil
+}
@@ -0,0 +1,123 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestParsePing(t *testing.T) {
+	buf := bytes.NewBufferString(`TITLE: title
+-----
+BODY:
+body
+-----
+PING:
+TITLE: Foo Bar
+URL: http://www.foo.com/baz/archives/000015.html
+IP: 123.102.3.4
+BLOG NAME: My Weblog
+DATE: 01/31/2002 03:31:05 PM
+This is the start of my
+entry, and here it...
+-----
+PING:
+TITLE: Second
+DATE: 02/01/2002 10:00:00
+second ping
+-----
+--------
+`)
+
+	expected := []Ping{
+		{
+			Title:    "Foo Bar",
+			URL:      "http://www.foo.com/baz/archives/000015.html",
+			IP:       "123.102.3.4",
+			BlogName: "My Weblog",
+			Date:     time.Date(2002, time.January, 31, 15, 31, 5, 0, time.UTC),
+			Body:     "This is the start of my\nentry, and here it...\n",
+			Raw:      "TITLE: Foo Bar\nURL: http://www.foo.com/baz/archives/000015.html\nIP: 123.102.3.4\nBLOG NAME: My Weblog\nDATE: 01/31/2002 03:31:05 PM\nThis is the start of my\nentry, and here it...\n",
+		},
+		{
+			Title: "Second",
+			Date:  time.Date(2002, time.February, 1, 10, 0, 0, 0, time.UTC),
+			Body:  "second ping\n",
+			Raw:   "TITLE: Second\nDATE: 02/01/2002 10:00:00\nsecond ping\n",
+		},
+	}
+
+	mts, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(mts[0].Pings, expected) {
+		t.Errorf("Error parsing, expected %v; got %v", expected, mts[0].Pings)
+	}
+}
+
+func TestParsePingInvalidDate(t *testing.T) {
+	buf := bytes.NewBufferString("PING:\nTITLE: Foo\nDATE: yesterday\nbody\n-----\n--------\n")
+
+	_, err := Parse(buf)
+	if err == nil {
+		t.Errorf("Invalid DATE of PING should be an error")
+	}
+}
+
+func TestWritePing(t *testing.T) {
+	input := "TITLE: title\n-----\nPING:\nTITLE: Foo Bar\nURL: http://www.foo.com/\nIP: 123.102.3.4\nBLOG NAME: My Weblog\nDATE: 01/31/2002 15:31:05\nexcerpt\n-----\n--------\n"
+
+	mts, err := Parse(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+}
+
+func TestParsePings(t *testing.T) {
+	raw := "PING:\nTITLE: Foo Bar\nURL: http://www.foo.com/\nIP: 123.102.3.4\nBLOG NAME: My Weblog\nDATE: 01/31/2002 15:31:05\nexcerpt\n-----\nPING:\nTITLE: second\n-----\n"
+
+	expected := []Trackback{
+		{
+			Title:    "Foo Bar",
+			URL:      "http://www.foo.com/",
+			IP:       "123.102.3.4",
+			BlogName: "My Weblog",
+			Date:     time.Date(2002, time.January, 31, 15, 31, 5, 0, time.UTC),
+			Body:     "excerpt\n",
+			Raw:      "TITLE: Foo Bar\nURL: http://www.foo.com/\nIP: 123.102.3.4\nBLOG NAME: My Weblog\nDATE: 01/31/2002 15:31:05\nexcerpt\n",
+		},
+		{
+			Title: "second",
+			Raw:   "TITLE: second\n",
+		},
+	}
+
+	pgs, err := ParsePings(raw)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(pgs, expected) {
+		t.Errorf("ParsePings got %v; want %v", pgs, expected)
+	}
+
+	_, err = ParsePings("DATE: yesterday\n")
+	if !IsParseError(err) {
+		t.Errorf("got error %v; want ParseError of DATE", err)
+	}
+}
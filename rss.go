@@ -0,0 +1,97 @@
+package movabletype
+
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
+
+// Maximum length in runes of the summary of an RSS item or Atom entry made
+// from the body
+const feedSummaryLength = 200
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	Description string    `xml:"description"`
+	Items       []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	XMLName     xml.Name `xml:"item"`
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category"`
+}
+
+// ToRSSItem returns e as an <item> element of RSS 2.0.
+//
+// link is UNIQUE URL, or BASENAME joined to baseURL when UNIQUE URL is
+// empty. description is ExcerptOrSummary cut to 200 runes. category is
+// written for each of AllCategories.
+func (e *Entry) ToRSSItem(baseURL string) string {
+	b, _ := xml.MarshalIndent(newRSSItem(e, baseURL), "", "\t")
+	return string(b)
+}
+
+// EntriesToRSS returns an RSS 2.0 document of a channel with title, link
+// and description, with an item of each entry as ToRSSItem. The links of
+// items are built from link.
+func EntriesToRSS(entries []*Entry, title, link, description string) (string, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:       title,
+			Link:        link,
+			Description: description,
+		},
+	}
+
+	for _, e := range entries {
+		doc.Channel.Items = append(doc.Channel.Items, newRSSItem(e, link))
+	}
+
+	b, err := xml.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return "", err
+	}
+
+	return xml.Header + string(b) + "\n", nil
+}
+
+func newRSSItem(e *Entry, baseURL string) rssItem {
+	item := rssItem{
+		Title:       e.Title,
+		Link:        rssLink(e, baseURL),
+		Description: e.ExcerptOrSummary(feedSummaryLength),
+		Author:      e.Author,
+		Categories:  e.AllCategories(),
+	}
+
+	if !e.Date.IsZero() {
+		item.PubDate = e.Date.Format(time.RFC1123Z)
+	}
+
+	return item
+}
+
+// rssLink returns the link of e for RSS items.
+func rssLink(e *Entry, baseURL string) string {
+	if e.UniqueURL != "" {
+		return e.UniqueURL
+	}
+	if baseURL == "" || e.Basename == "" {
+		return ""
+	}
+
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(e.Basename, "/")
+}
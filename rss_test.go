@@ -0,0 +1,94 @@
+package movabletype_test
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestToRSSItem(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	m := mts[0]
+	m.Title = "ポエム & <詩>"
+
+	expected := `<item>
+	<title>ポエム &amp; &lt;詩&gt;</title>
+	<link>https://example.com/blog/poem</link>
+	<description>body</description>
+	<pubDate>Sat, 22 Apr 2017 20:41:58 +0000</pubDate>
+	<author>catatsuy</author>
+	<category>ブログ</category>
+	<category>ポエム</category>
+	<category>技術系</category>
+</item>`
+	if got := m.ToRSSItem("https://example.com/blog/"); got != expected {
+		t.Errorf("ToRSSItem expected\n%s\ngot\n%s", expected, got)
+	}
+
+	e := NewEntry()
+	e.Title = "no link"
+	e.Excerpt = "excerpt\n"
+	expected = `<item>
+	<title>no link</title>
+	<description>excerpt</description>
+</item>`
+	if got := e.ToRSSItem("https://example.com/"); got != expected {
+		t.Errorf("ToRSSItem without BASENAME expected\n%s\ngot\n%s", expected, got)
+	}
+
+	e.UniqueURL = "https://example.typepad.com/blog/2014/05/post.html"
+	if got := e.ToRSSItem(""); !strings.Contains(got, "<link>https://example.typepad.com/blog/2014/05/post.html</link>") {
+		t.Errorf("ToRSSItem should use UNIQUE URL, got\n%s", got)
+	}
+}
+
+func TestEntriesToRSS(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	output, err := EntriesToRSS(mts, "catatsuy's blog", "https://example.com", "Blog & notes")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	d := xml.NewDecoder(strings.NewReader(output))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("RSS should be well-formed, got error %q\n%s", err, output)
+		}
+	}
+
+	expected := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`<rss version="2.0">`,
+		`<title>catatsuy&#39;s blog</title>`,
+		`<link>https://example.com</link>`,
+		`<description>Blog &amp; notes</description>`,
+		`<link>https://example.com/poem</link>`,
+		`<link>https://example.com/2017/04/09/194939</link>`,
+		`<pubDate>Sun, 09 Apr 2017 19:49:39 +0000</pubDate>`,
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("RSS should contain %q, got\n%s", s, output)
+		}
+	}
+
+	if strings.Count(output, "<item>") != len(mts) {
+		t.Errorf("RSS should have %d items, got\n%s", len(mts), output)
+	}
+}
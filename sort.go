@@ -0,0 +1,63 @@
+package movabletype
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// SortByDate sorts entries in place by DATE.
+func SortByDate(entries []*Entry, ascending bool) {
+	sortEntries(entries, ascending, func(a, b *Entry) bool {
+		return a.Date.Before(b.Date)
+	})
+}
+
+// SortByTitle sorts entries in place by TITLE.
+func SortByTitle(entries []*Entry, ascending bool) {
+	sortEntries(entries, ascending, func(a, b *Entry) bool {
+		return a.Title < b.Title
+	})
+}
+
+// SortByAuthor sorts entries in place by AUTHOR.
+func SortByAuthor(entries []*Entry, ascending bool) {
+	sortEntries(entries, ascending, func(a, b *Entry) bool {
+		return a.Author < b.Author
+	})
+}
+
+// SortEntries sorts entries in place by field, which is one of "date",
+// "title" and "author". Field names are case-insensitive.
+func SortEntries(entries []*Entry, field string, ascending bool) error {
+	switch strings.ToLower(field) {
+	case "date":
+		SortByDate(entries, ascending)
+	case "title":
+		SortByTitle(entries, ascending)
+	case "author":
+		SortByAuthor(entries, ascending)
+	default:
+		return errors.Errorf("Unknown sort field %s", field)
+	}
+
+	return nil
+}
+
+// Sort sorts entries in place with less, which reports whether a must come
+// before b. Entries for which less is false both ways keep their order.
+func Sort(entries []*Entry, less func(a, b *Entry) bool) {
+	sortEntries(entries, true, less)
+}
+
+// sortEntries sorts entries in place with less, or in reverse order unless
+// ascending. Entries which are equal keep their order.
+func sortEntries(entries []*Entry, ascending bool, less func(a, b *Entry) bool) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if ascending {
+			return less(entries[i], entries[j])
+		}
+		return less(entries[j], entries[i])
+	})
+}
@@ -0,0 +1,81 @@
+package movabletype_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func sortFixture() []*Entry {
+	return []*Entry{
+		{Title: "b", Author: "foo", Date: time.Date(2017, time.April, 15, 0, 0, 0, 0, time.UTC)},
+		{Title: "c", Author: "catatsuy", Date: time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)},
+		{Title: "a", Author: "catatsuy", Date: time.Date(2017, time.May, 1, 0, 0, 0, 0, time.UTC)},
+	}
+}
+
+func TestSort(t *testing.T) {
+	var featuretests = []struct {
+		field     string
+		ascending bool
+		titles    []string
+	}{
+		{"date", true, []string{"c", "b", "a"}},
+		{"date", false, []string{"a", "b", "c"}},
+		{"title", true, []string{"a", "b", "c"}},
+		{"TITLE", false, []string{"c", "b", "a"}},
+		{"author", true, []string{"c", "a", "b"}},
+		{"author", false, []string{"b", "c", "a"}},
+	}
+
+	for _, ft := range featuretests {
+		entries := sortFixture()
+
+		err := SortEntries(entries, ft.field, ft.ascending)
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		got := titles(entries)
+		for i := range got {
+			if got[i] != ft.titles[i] {
+				t.Errorf("SortEntries(%q, %v) got %q; want %q", ft.field, ft.ascending, got, ft.titles)
+				break
+			}
+		}
+	}
+}
+
+func TestSortEntriesUnknownField(t *testing.T) {
+	err := SortEntries(sortFixture(), "basename", true)
+	if err == nil || err.Error() != "Unknown sort field basename" {
+		t.Errorf("got error %v; want Unknown sort field basename", err)
+	}
+}
+
+func TestSortStable(t *testing.T) {
+	d1 := time.Date(2017, time.April, 1, 0, 0, 0, 0, time.UTC)
+	d2 := time.Date(2017, time.April, 2, 0, 0, 0, 0, time.UTC)
+
+	entries := []*Entry{
+		{Title: "1", Date: d2},
+		{Title: "2", Date: d1},
+		{Title: "3", Date: d2},
+		{Title: "4", Date: d1},
+		{Title: "5", Date: d2},
+	}
+
+	SortByDate(entries, true)
+	if got, expected := titles(entries), []string{"2", "4", "1", "3", "5"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("SortByDate got %q; want %q", got, expected)
+	}
+
+	Sort(entries, func(a, b *Entry) bool {
+		return a.Date.After(b.Date)
+	})
+	if got, expected := titles(entries), []string{"1", "3", "5", "2", "4"}; !reflect.DeepEqual(got, expected) {
+		t.Errorf("Sort got %q; want %q", got, expected)
+	}
+}
@@ -0,0 +1,71 @@
+package movabletype
+
+import "strings"
+
+// parseTags splits the value of the TAGS column.
+// Tags are separated by commas and may be quoted with double quotes
+// when they contain commas or spaces.
+func parseTags(value string) []string {
+	var tags []string
+
+	add := func(tag string) {
+		tag = strings.TrimSpace(tag)
+		if len(tag) >= 2 && strings.HasPrefix(tag, `"`) && strings.HasSuffix(tag, `"`) {
+			tag = tag[1 : len(tag)-1]
+		}
+		if tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+
+	quoted := false
+	start := 0
+	for i, r := range value {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				add(value[start:i])
+				start = i + 1
+			}
+		}
+	}
+	add(value[start:])
+
+	return tags
+}
+
+// formatTags joins tags for the TAGS column.
+func formatTags(tags []string) string {
+	ss := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		if strings.ContainsAny(tag, ", ") {
+			tag = `"` + tag + `"`
+		}
+		ss = append(ss, tag)
+	}
+
+	return strings.Join(ss, ",")
+}
+
+// HasTag reports whether e has tag in TAGS.
+func (e *Entry) HasTag(tag string) bool {
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTag adds tag to TAGS unless e already has it.
+// Leading and trailing spaces of tag are trimmed.
+func (e *Entry) AddTag(tag string) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || e.HasTag(tag) {
+		return
+	}
+	e.Tags = append(e.Tags, tag)
+}
@@ -0,0 +1,92 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestParseTags(t *testing.T) {
+	var featuretests = []struct {
+		value string
+		tags  []string
+	}{
+		{`"go","movable type",tutorial`, []string{"go", "movable type", "tutorial"}},
+		{`golang, "New York" , 機械学習`, []string{"golang", "New York", "機械学習"}},
+		{`"a,b",c`, []string{"a,b", "c"}},
+		{`tutorial`, []string{"tutorial"}},
+		{`"機械学習",golang,"New York"`, []string{"機械学習", "golang", "New York"}},
+		{``, nil},
+	}
+
+	for _, ft := range featuretests {
+		mts, err := Parse(bytes.NewBufferString("TAGS: " + ft.value + "\n--------\n"))
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		if !reflect.DeepEqual(mts[0].Tags, ft.tags) {
+			t.Errorf("TAGS %s got %q; want %q", ft.value, mts[0].Tags, ft.tags)
+		}
+	}
+}
+
+func TestWriteTags(t *testing.T) {
+	m := NewEntry()
+	m.Tags = []string{"go", "movable type", "a,b"}
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, []*Entry{m})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "TAGS: go,\"movable type\",\"a,b\"\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+
+	mts, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if !reflect.DeepEqual(mts[0].Tags, m.Tags) {
+		t.Errorf("Error parsing written tags, expected %q; got %q", m.Tags, mts[0].Tags)
+	}
+}
+
+func TestParseTagsEmpty(t *testing.T) {
+	mts, err := Parse(bytes.NewBufferString("TAGS:\n--------\n"))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Tags != nil {
+		t.Errorf("Empty TAGS should be nil, got %q", mts[0].Tags)
+	}
+}
+
+func TestAddTag(t *testing.T) {
+	m := NewEntry()
+
+	if m.HasTag("go") {
+		t.Errorf("New entry should not have tags")
+	}
+
+	m.AddTag("go")
+	m.AddTag(" movable type ")
+	m.AddTag("go")
+	m.AddTag("")
+
+	expected := []string{"go", "movable type"}
+	if !reflect.DeepEqual(m.Tags, expected) {
+		t.Errorf("m.Tags got %q; want %q", m.Tags, expected)
+	}
+
+	if !m.HasTag("movable type") {
+		t.Errorf("m.HasTag(%q) got false; want true", "movable type")
+	}
+}
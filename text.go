@@ -0,0 +1,110 @@
+package movabletype
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DefaultWordsPerMinute is the reading speed used by ReadingTime when wpm
+// is not positive.
+const DefaultWordsPerMinute = 200
+
+var htmlTagRegexp = regexp.MustCompile(`<[^>]+>`)
+
+// stripTags removes HTML tags from s. Each tag is replaced with a space so
+// that words around block tags are not joined.
+func stripTags(s string) string {
+	return htmlTagRegexp.ReplaceAllString(s, " ")
+}
+
+// WordCount returns the number of words in FullBody after HTML tags are
+// removed. Words are separated by white space.
+func (e *Entry) WordCount() int {
+	return len(strings.Fields(stripTags(e.FullBody())))
+}
+
+// ReadingTime returns the time to read FullBody at wpm words per minute.
+func (e *Entry) ReadingTime(wpm int) time.Duration {
+	if wpm <= 0 {
+		wpm = DefaultWordsPerMinute
+	}
+
+	return time.Duration(e.WordCount()) * time.Minute / time.Duration(wpm)
+}
+
+// BodyText returns Body as plain text. HTML tags are removed, with block
+// tags such as <p> and <br> breaking lines, entities such as &amp; are
+// unescaped, and spaces are collapsed. Empty lines are removed.
+func (e *Entry) BodyText() string {
+	return htmlToText(e.Body)
+}
+
+// ExtendedBodyText returns ExtendedBody as plain text like BodyText.
+func (e *Entry) ExtendedBodyText() string {
+	return htmlToText(e.ExtendedBody)
+}
+
+// FullBodyText returns FullBody as plain text like BodyText.
+func (e *Entry) FullBodyText() string {
+	return htmlToText(e.FullBody())
+}
+
+var htmlTagNameRegexp = regexp.MustCompile(`^</?\s*([a-zA-Z0-9]+)`)
+
+// Tags which break lines in htmlToText
+var blockTags = map[string]bool{
+	"address": true, "blockquote": true, "br": true, "dd": true, "div": true,
+	"dl": true, "dt": true, "h1": true, "h2": true, "h3": true, "h4": true,
+	"h5": true, "h6": true, "hr": true, "li": true, "ol": true, "p": true,
+	"pre": true, "table": true, "td": true, "th": true, "tr": true, "ul": true,
+}
+
+func htmlToText(s string) string {
+	s = htmlTagRegexp.ReplaceAllStringFunc(s, func(tag string) string {
+		if m := htmlTagNameRegexp.FindStringSubmatch(tag); m != nil && blockTags[strings.ToLower(m[1])] {
+			return "\n"
+		}
+		return ""
+	})
+
+	var lines []string
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.Join(strings.Fields(html.UnescapeString(line)), " ")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// ExcerptOrSummary returns EXCERPT with leading and trailing spaces
+// trimmed. If EXCERPT is empty, it returns BodyText on one line, cut to
+// maxLen runes at a word boundary with "…" appended when it is longer.
+// Text without spaces such as Japanese is cut at maxLen runes. If maxLen is
+// not positive, the text is not cut.
+func (e *Entry) ExcerptOrSummary(maxLen int) string {
+	if excerpt := strings.TrimSpace(e.Excerpt); excerpt != "" {
+		return excerpt
+	}
+
+	text := []rune(strings.Join(strings.Fields(e.BodyText()), " "))
+	if maxLen <= 0 || len(text) <= maxLen {
+		return string(text)
+	}
+
+	cut := maxLen
+	if text[cut] != ' ' {
+		for i := cut - 1; i > 0; i-- {
+			if text[i] == ' ' {
+				cut = i
+				break
+			}
+		}
+	}
+
+	return strings.TrimSpace(string(text[:cut])) + "…"
+}
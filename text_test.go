@@ -0,0 +1,80 @@
+package movabletype_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestWordCount(t *testing.T) {
+	m := NewEntry()
+	m.Body = "<p>Hello, <a href=\"https://example.com/\">Movable Type</a></p>\n"
+	m.ExtendedBody = "<p>three more words</p><p>and two</p>\n"
+
+	if m.WordCount() != 8 {
+		t.Errorf("WordCount got %d; want %d", m.WordCount(), 8)
+	}
+
+	if got := m.ReadingTime(4); got != 2*time.Minute {
+		t.Errorf("ReadingTime(4) got %v; want %v", got, 2*time.Minute)
+	}
+
+	if got := m.ReadingTime(0); got != 8*time.Minute/DefaultWordsPerMinute {
+		t.Errorf("ReadingTime(0) got %v; want %v", got, 8*time.Minute/DefaultWordsPerMinute)
+	}
+
+	if NewEntry().WordCount() != 0 {
+		t.Errorf("WordCount of an empty entry got %d; want 0", NewEntry().WordCount())
+	}
+}
+
+func TestBodyText(t *testing.T) {
+	m := NewEntry()
+	m.Body = "<p>Tom &amp; Jerry</p>\n<p>Use <code>&lt;p&gt;</code> for <em>paragraphs</em>.</p>\n"
+	m.ExtendedBody = "<p>extended\n\n  body</p><p>line<br />break</p>\n"
+
+	var featuretests = []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"BodyText", m.BodyText(), "Tom & Jerry\nUse <p> for paragraphs."},
+		{"ExtendedBodyText", m.ExtendedBodyText(), "extended\nbody\nline\nbreak"},
+		{"FullBodyText", m.FullBodyText(), "Tom & Jerry\nUse <p> for paragraphs.\nextended\nbody\nline\nbreak"},
+	}
+
+	for _, ft := range featuretests {
+		if ft.got != ft.expected {
+			t.Errorf("%s got %q; want %q", ft.name, ft.got, ft.expected)
+		}
+	}
+}
+
+func TestExcerptOrSummary(t *testing.T) {
+	var featuretests = []struct {
+		excerpt, body string
+		maxLen        int
+		expected      string
+	}{
+		{"  excerpt\n", "<p>body</p>", 3, "excerpt"},
+		{"", "<p>The quick brown fox</p>\n<p>jumps</p>", 12, "The quick…"},
+		{"", "<p>The quick brown fox</p>", 9, "The quick…"},
+		{"", "<p>The quick brown fox</p>", 100, "The quick brown fox"},
+		{"", "<p>風邪で声を失った話</p>", 4, "風邪で声…"},
+		{"", "", 10, ""},
+		{"", "<p>The quick brown fox</p>", 0, "The quick brown fox"},
+		{"", "<p>The quick brown fox</p>", -1, "The quick brown fox"},
+		{"excerpt", "<p>body</p>", -1, "excerpt"},
+	}
+
+	for _, ft := range featuretests {
+		m := NewEntry()
+		m.Excerpt = ft.excerpt
+		m.Body = ft.body
+
+		if got := m.ExcerptOrSummary(ft.maxLen); got != ft.expected {
+			t.Errorf("ExcerptOrSummary(%d) of %q got %q; want %q", ft.maxLen, ft.body, got, ft.expected)
+		}
+	}
+}
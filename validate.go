@@ -0,0 +1,67 @@
+package movabletype
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EntryValidator checks an entry and returns an error if it is invalid.
+type EntryValidator func(e *Entry) error
+
+// ValidationError lists the constraints an entry does not satisfy
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid entry: " + strings.Join(e.Problems, ", ")
+}
+
+// Validate checks that the entry is complete to be published.
+// TITLE, AUTHOR and DATE must be set, STATUS must be Draft or Publish or
+// Future, and ALLOW COMMENTS and ALLOW PINGS must be 0, 1 or 2 if they are
+// set. DefaultAllowComments and DefaultAllowPings mean they are not set.
+// It returns *ValidationError listing every failing constraint.
+func (e *Entry) Validate() error {
+	return e.validate(true, nil)
+}
+
+// validate checks e as Validate. AUTHOR is required only if requireAuthor,
+// and statuses are accepted in addition to Draft, Publish and Future.
+func (e *Entry) validate(requireAuthor bool, statuses []string) error {
+	var problems []string
+
+	if e.Title == "" {
+		problems = append(problems, "TITLE is empty")
+	}
+	if requireAuthor && e.Author == "" {
+		problems = append(problems, "AUTHOR is empty")
+	}
+	if e.Date.IsZero() {
+		problems = append(problems, "DATE is not set")
+	}
+	if !e.Status.Valid() && !containsString(statuses, string(e.Status)) {
+		problems = append(problems, fmt.Sprintf("STATUS is allowed only Draft or Publish or Future. Got %q", e.Status))
+	}
+	if e.AllowComments != DefaultAllowComments && !validAllow(e.AllowComments) {
+		problems = append(problems, fmt.Sprintf("ALLOW COMMENTS is allowed only 0, 1 or 2. Got %d", e.AllowComments))
+	}
+	if e.AllowPings != DefaultAllowPings && !validAllow(e.AllowPings) {
+		problems = append(problems, fmt.Sprintf("ALLOW PINGS is allowed only 0, 1 or 2. Got %d", e.AllowPings))
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
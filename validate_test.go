@@ -0,0 +1,144 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestValidate(t *testing.T) {
+	m := NewEntry()
+	m.Title = "title"
+	m.Author = "catatsuy"
+	m.Date = time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)
+	m.Status = "Publish"
+	m.AllowComments = 1
+	m.AllowPings = 0
+
+	if err := m.Validate(); err != nil {
+		t.Errorf("got error %q", err)
+	}
+}
+
+func TestValidateProblems(t *testing.T) {
+	m := NewEntry()
+	m.Title = "title"
+
+	err := m.Validate()
+
+	ve, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("got %v; want ValidationError", err)
+	}
+
+	expected := []string{
+		"AUTHOR is empty",
+		"DATE is not set",
+		`STATUS is allowed only Draft or Publish or Future. Got ""`,
+	}
+	if !reflect.DeepEqual(ve.Problems, expected) {
+		t.Errorf("Problems got %q; want %q", ve.Problems, expected)
+	}
+}
+
+func TestValidateAllow(t *testing.T) {
+	var featuretests = []struct {
+		allowComments, allowPings int
+		problems                  []string
+	}{
+		{DefaultAllowComments, DefaultAllowPings, nil},
+		{0, 2, nil},
+		{3, DefaultAllowPings, []string{"ALLOW COMMENTS is allowed only 0, 1 or 2. Got 3"}},
+		{1, -2, []string{"ALLOW PINGS is allowed only 0, 1 or 2. Got -2"}},
+	}
+
+	for _, ft := range featuretests {
+		m := NewEntry()
+		m.Title = "title"
+		m.Author = "catatsuy"
+		m.Date = time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC)
+		m.Status = StatusPublish
+		m.AllowComments = ft.allowComments
+		m.AllowPings = ft.allowPings
+
+		var problems []string
+		if ve, ok := m.Validate().(*ValidationError); ok {
+			problems = ve.Problems
+		}
+		if !reflect.DeepEqual(problems, ft.problems) {
+			t.Errorf("ALLOW COMMENTS %d, ALLOW PINGS %d got %q; want %q", ft.allowComments, ft.allowPings, problems, ft.problems)
+		}
+	}
+}
+
+func TestParseWithValidator(t *testing.T) {
+	requireBasename := func(e *Entry) error {
+		if e.Basename == "" {
+			return errors.New("BASENAME is empty")
+		}
+		return nil
+	}
+
+	buf := bytes.NewBufferString("TITLE: first\nBASENAME: first\n-----\n--------\nTITLE: second\n-----\n--------\n")
+
+	_, err := Parse(buf, WithValidator(requireBasename))
+
+	var pe *ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v; want ParseError", err)
+	}
+
+	if pe.Line != 7 || pe.Err.Error() != "BASENAME is empty" {
+		t.Errorf("got line %d error %q", pe.Line, pe.Err)
+	}
+
+	buf = bytes.NewBufferString("TITLE: first\nBASENAME: first\n-----\n--------\nTITLE: second\n-----\n--------\n")
+
+	mts, err := Parse(buf, WithValidator(requireBasename), func(o *ParseOptions) { o.ContinueOnError = true })
+	if len(mts) != 1 || mts[0].Title != "first" {
+		t.Errorf("Valid entries should be returned, got %v", mts)
+	}
+
+	if _, ok := err.(MultiError); !ok {
+		t.Errorf("got %v; want MultiError", err)
+	}
+}
+
+func TestParseValidateOnParse(t *testing.T) {
+	buf := bytes.NewBufferString("AUTHOR: catatsuy\nSTATUS: Publish\n-----\n--------\n")
+
+	_, err := ParseWithOptions(buf, ParseOptions{ValidateOnParse: true})
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("got %v; want ValidationError", err)
+	}
+
+	expected := []string{
+		"TITLE is empty",
+		"DATE is not set",
+	}
+	if !reflect.DeepEqual(ve.Problems, expected) {
+		t.Errorf("Problems got %q; want %q", ve.Problems, expected)
+	}
+}
+
+func TestParseValidateOnParseValidExport(t *testing.T) {
+	mts, err := ParseString(sampleExport, WithValidateOnParse())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if len(mts) != 2 {
+		t.Errorf("got %d entries; want 2", len(mts))
+	}
+
+	_, err = ParseString("TITLE: no author\nSTATUS: Draft\nDATE: 04/22/2017 20:41:58\n-----\n--------\n", WithValidateOnParse())
+	if err != nil {
+		t.Errorf("AUTHOR should not be required, got error %q", err)
+	}
+}
@@ -0,0 +1,103 @@
+package movabletype
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// WarningCode classifies Warning
+type WarningCode string
+
+// Codes of Warning
+const (
+	// A column with an invalid value is left at its default by SkipInvalid
+	WarningInvalidValue WarningCode = "invalid_value"
+
+	// A column not known by this package, kept in Entry.Extra
+	WarningUnknownKey WarningCode = "unknown_key"
+
+	// A single-line column such as TITLE appears more than once in an
+	// entry. The last one is used.
+	WarningDuplicateField WarningCode = "duplicate_field"
+
+	// DATE is parsed with a layout other than the one written by Movable
+	// Type, such as "4/9/2017 19:49"
+	WarningDateFallback WarningCode = "date_fallback"
+
+	// A COMMENT block has no AUTHOR
+	WarningEmptyCommentAuthor WarningCode = "empty_comment_author"
+
+	// BODY is empty while EXTENDED BODY is not, which usually means the
+	// input is malformed
+	WarningEmptyBody WarningCode = "empty_body"
+)
+
+// Warning is a problem of the input which did not stop the parse, such as
+// an unknown column or an invalid value skipped by SkipInvalid.
+type Warning struct {
+	Code WarningCode
+
+	// Line number (1-based) of the problem
+	Line int
+
+	// Entry number (1-based) in the input of the problem
+	EntryIndex int
+
+	// Column name such as STATUS or DATE, if any
+	Field string
+
+	// Offending value of the column, if any
+	Value string
+
+	// Message describes the problem
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d, entry %d: %s", w.Line, w.EntryIndex, w.Message)
+}
+
+// Warnings returns the warnings reported so far. Warnings are kept only
+// when OnWarning is not set, so that a Parser streaming a large input with
+// OnWarning does not accumulate them. Parse and the other functions which
+// do not return the Parser keep no warnings; use OnWarning with them.
+func (p *Parser) Warnings() []Warning {
+	return p.warnings
+}
+
+// warn passes w to OnWarning, or records it if OnWarning is not set and
+// the Parser is returned to the caller by NewParser.
+func (p *Parser) warn(w Warning) {
+	if p.opts.OnWarning != nil {
+		p.opts.OnWarning(w)
+		return
+	}
+	if p.keepWarnings {
+		p.warnings = append(p.warnings, w)
+	}
+}
+
+// warnError records err of an invalid column as WarningInvalidValue.
+func (p *Parser) warnError(err error) {
+	w := Warning{
+		Code:       WarningInvalidValue,
+		Line:       p.line,
+		EntryIndex: p.entry,
+		Message:    err.Error(),
+	}
+
+	var pe *ParseError
+	if errors.As(err, &pe) {
+		w.Line = pe.Line
+		w.EntryIndex = pe.EntryIndex
+		w.Field = pe.Field
+		w.Value = pe.Value
+		w.Message = pe.Reason
+		if pe.Err != nil {
+			w.Message += ": " + pe.Err.Error()
+		}
+	}
+
+	p.warn(w)
+}
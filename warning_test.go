@@ -0,0 +1,160 @@
+package movabletype_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestWarningHandler(t *testing.T) {
+	input := `TITLE: first
+TITLE: second
+FAVORITE: 1
+DATE: 4/9/2017 19:49
+-----
+COMMENT:
+EMAIL: foo@example.com
+anonymous comment
+-----
+--------
+TITLE: title
+DATE: 04/09/2017 07:49:39 PM
+-----
+--------
+`
+
+	var warnings []Warning
+	mts, err := ParseString(input, WithWarningHandler(func(w Warning) {
+		warnings = append(warnings, w)
+	}))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Title != "second" {
+		t.Errorf("The last TITLE should be used, got %q", mts[0].Title)
+	}
+
+	var featuretests = []struct {
+		code  WarningCode
+		field string
+		line  int
+	}{
+		{WarningDuplicateField, "TITLE", 2},
+		{WarningUnknownKey, "FAVORITE", 3},
+		{WarningDateFallback, "DATE", 4},
+		{WarningEmptyCommentAuthor, "COMMENT", 6},
+	}
+
+	if len(warnings) != len(featuretests) {
+		t.Fatalf("got warnings %v; want %d warnings", warnings, len(featuretests))
+	}
+
+	for i, ft := range featuretests {
+		w := warnings[i]
+		if w.Code != ft.code || w.Field != ft.field || w.Line != ft.line || w.EntryIndex != 1 {
+			t.Errorf("warning %d got %+v; want %s of %s at line %d", i, w, ft.code, ft.field, ft.line)
+		}
+	}
+
+	if warnings[2].Message != "DATE column is not in the format 01/02/2006 15:04:05" {
+		t.Errorf("w.Message got %q", warnings[2].Message)
+	}
+}
+
+func TestWarningEmptyBody(t *testing.T) {
+	input := `TITLE: no body
+-----
+BODY:
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+--------
+TITLE: body
+-----
+BODY:
+<p>body</p>
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+--------
+TITLE: extended only
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+`
+
+	p := NewParser(strings.NewReader(input))
+	mts, err := p.All()
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if len(mts) != 3 {
+		t.Fatalf("got %d entries; want 3", len(mts))
+	}
+
+	warnings := p.Warnings()
+	if len(warnings) != 2 {
+		t.Fatalf("got warnings %v; want 2 warnings", warnings)
+	}
+
+	var featuretests = []struct {
+		entry, line int
+	}{
+		{1, 5},
+		{3, 20},
+	}
+
+	for i, ft := range featuretests {
+		w := warnings[i]
+		if w.Code != WarningEmptyBody || w.Field != "BODY" || w.EntryIndex != ft.entry || w.Line != ft.line || w.Message != "empty BODY with EXTENDED BODY" {
+			t.Errorf("warning %d got %+v; want %s of entry %d at line %d", i, w, WarningEmptyBody, ft.entry, ft.line)
+		}
+	}
+}
+
+func TestWarningsNotKeptWithHandler(t *testing.T) {
+	input := "TITLE: title\nFAVORITE: 1\n-----\n--------\n"
+
+	count := 0
+	p := NewParser(strings.NewReader(input), WithWarningHandler(func(w Warning) { count++ }))
+	if _, err := p.All(); err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if count != 1 || len(p.Warnings()) != 0 {
+		t.Errorf("got %d warnings passed to the handler and %v kept; want 1 and none", count, p.Warnings())
+	}
+
+	p = NewParser(strings.NewReader(input))
+	if _, err := p.All(); err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if len(p.Warnings()) != 1 {
+		t.Errorf("p.Warnings() got %v; want 1 warning", p.Warnings())
+	}
+}
+
+func TestWarningsNotKeptByParse(t *testing.T) {
+	input := strings.Repeat("TITLE: title\nFAVORITE: 1\n-----\n--------\n", 3)
+
+	p := NewParserOfParse(strings.NewReader(input), ParseOptions{})
+	mts, err := p.All()
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if len(mts) != 3 || len(p.Warnings()) != 0 {
+		t.Errorf("Parse should keep no warnings, got %d entries and %v", len(mts), p.Warnings())
+	}
+
+	p = NewDecoder(strings.NewReader(input))
+	if _, err := p.All(); err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if len(p.Warnings()) != 3 {
+		t.Errorf("p.Warnings() got %v; want 3 warnings", p.Warnings())
+	}
+}
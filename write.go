@@ -0,0 +1,222 @@
+package movabletype
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout of the DATE column written by Write
+const dateFormat = "01/02/2006 15:04:05"
+
+// WriteOptions configures WriteWithOptions.
+type WriteOptions struct {
+	// IncludeDefaults writes empty fields and a zero DATE instead of
+	// omitting them. An empty STATUS and ALLOW COMMENTS / ALLOW PINGS left
+	// at their default (-1) are still omitted because they are not valid
+	// values in the format.
+	IncludeDefaults bool
+
+	// DateOffset writes the UTC offset after dates outside UTC such as
+	// "04/22/2017 20:41:58 +0900", which Parse reads back as the same
+	// instant. The importer of Movable Type does not accept it.
+	// false (default): dates are written in their wall-clock time without
+	// the offset.
+	DateOffset bool
+}
+
+// Write writes entries to w in the Movable Type Import / Export Format.
+//
+// Fields that are empty or left at their default value are omitted. Fields
+// are written in the order of the exporter of Movable Type so that an
+// export written back has minimal diffs: AUTHOR, TITLE, BASENAME, UNIQUE
+// URL, STATUS, ALLOW COMMENTS, ALLOW PINGS, CONVERT BREAKS, DATE, PRIMARY
+// CATEGORY, CATEGORY, TAGS and IMAGE, followed by the other columns. Then
+// come the blocks BODY, EXTENDED BODY, EXCERPT, KEYWORDS, COMMENT and PING.
+//
+// DATE is always written in the 24-hour layout 01/02/2006 15:04:05, which
+// Movable Type also reads. A DATE such as 04/09/2017 07:49:39 PM is written
+// back as 04/09/2017 19:49:39, so the output is not byte-identical to such
+// input. Dates are written in their wall-clock time, such as the local time
+// of dates parsed with WithLocation.
+func Write(w io.Writer, entries []*Entry) error {
+	return WriteWithOptions(w, entries, WriteOptions{})
+}
+
+// WriteWithOptions writes entries to w in the Movable Type Import / Export
+// Format using opts.
+func WriteWithOptions(w io.Writer, entries []*Entry, opts WriteOptions) error {
+	ew := &writer{Writer: bufio.NewWriter(w), opts: opts}
+
+	for _, e := range entries {
+		ew.writeEntry(e)
+		ew.WriteString("--------\n")
+	}
+
+	return ew.Flush()
+}
+
+// Bytes returns e in the Movable Type Import / Export Format without the
+// "--------" separator which Write puts after each entry.
+func (e *Entry) Bytes() []byte {
+	buf := &bytes.Buffer{}
+
+	ew := &writer{Writer: bufio.NewWriter(buf)}
+	ew.writeEntry(e)
+	ew.Flush()
+
+	return buf.Bytes()
+}
+
+type writer struct {
+	*bufio.Writer
+	opts WriteOptions
+}
+
+// writeEntry writes the fields and blocks of e.
+func (w *writer) writeEntry(e *Entry) {
+	w.writeField("AUTHOR", e.Author)
+	w.writeField("TITLE", e.Title)
+	w.writeField("BASENAME", e.Basename)
+	w.writeField("UNIQUE URL", e.UniqueURL)
+	if e.Status != "" {
+		w.writeField("STATUS", string(e.Status))
+	}
+	if e.AllowComments != DefaultAllowComments {
+		w.writeField("ALLOW COMMENTS", strconv.Itoa(e.AllowComments))
+	}
+	if e.AllowPings != DefaultAllowPings {
+		w.writeField("ALLOW PINGS", strconv.Itoa(e.AllowPings))
+	}
+	w.writeField("CONVERT BREAKS", string(e.ConvertBreaks))
+	if !e.Date.IsZero() || w.opts.IncludeDefaults {
+		w.writeField("DATE", w.formatDate(e.Date))
+	}
+	w.writeField("PRIMARY CATEGORY", e.PrimaryCategory)
+	for _, c := range e.Category {
+		w.writeField("CATEGORY", c)
+	}
+	w.writeField("TAGS", formatTags(e.Tags))
+	for _, image := range e.images() {
+		w.writeField("IMAGE", image)
+	}
+	if e.NoEntry {
+		w.writeField("NO ENTRY", "1")
+	}
+	for _, kv := range e.Extra {
+		w.writeField(kv.Key, kv.Value)
+	}
+	keys := make([]string, 0, len(e.CustomFields))
+	for key := range e.CustomFields {
+		if !e.hasExtra(key) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		w.writeField(key, e.CustomFields[key])
+	}
+	w.WriteString("-----\n")
+
+	w.writeBlock("BODY", e.Body)
+	w.writeBlock("EXTENDED BODY", e.ExtendedBody)
+	w.writeBlock("EXCERPT", e.Excerpt)
+	w.writeBlock("KEYWORDS", e.Keywords)
+	for _, c := range e.Comments {
+		w.writeBlock("COMMENT", w.commentText(c))
+	}
+	w.WriteString(e.Comment)
+	for _, pg := range e.Pings {
+		w.writeBlock("PING", w.pingText(pg))
+	}
+	names := make([]string, 0, len(e.ExtraBlocks))
+	for name := range e.ExtraBlocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, block := range e.ExtraBlocks[name] {
+			w.writeBlock(name, block)
+		}
+	}
+}
+
+// formatDate formats t for DATE columns. With DateOffset, dates outside UTC
+// are followed by their UTC offset.
+func (w *writer) formatDate(t time.Time) string {
+	if w.opts.DateOffset && t.Location() != time.UTC {
+		return t.Format(dateFormat + " -0700")
+	}
+	return t.Format(dateFormat)
+}
+
+// writeField writes a single-line field. Empty values are omitted unless
+// IncludeDefaults is set.
+func (w *writer) writeField(key, value string) {
+	if value == "" && !w.opts.IncludeDefaults {
+		return
+	}
+
+	w.WriteString(key + ": " + value + "\n")
+}
+
+// writeBlock writes a multi-line field followed by the "-----" separator.
+// Empty values are omitted unless IncludeDefaults is set.
+func (w *writer) writeBlock(key, value string) {
+	if value == "" && !w.opts.IncludeDefaults {
+		return
+	}
+
+	w.WriteString(key + ":\n")
+	w.WriteString(value)
+	if value != "" && !strings.HasSuffix(value, "\n") {
+		w.WriteString("\n")
+	}
+	w.WriteString("-----\n")
+}
+
+// commentText renders the text of a COMMENT block.
+func (w *writer) commentText(c Comment) string {
+	b := &strings.Builder{}
+
+	w.writeHeader(b, "AUTHOR", c.Author)
+	w.writeHeader(b, "EMAIL", c.Email)
+	w.writeHeader(b, "IP", c.IP)
+	w.writeHeader(b, "URL", c.URL)
+	if !c.Date.IsZero() {
+		w.writeHeader(b, "DATE", w.formatDate(c.Date))
+	}
+	b.WriteString(c.Body)
+
+	return b.String()
+}
+
+// pingText renders the text of a PING block.
+func (w *writer) pingText(pg Ping) string {
+	b := &strings.Builder{}
+
+	w.writeHeader(b, "TITLE", pg.Title)
+	w.writeHeader(b, "URL", pg.URL)
+	w.writeHeader(b, "IP", pg.IP)
+	w.writeHeader(b, "BLOG NAME", pg.BlogName)
+	if !pg.Date.IsZero() {
+		w.writeHeader(b, "DATE", w.formatDate(pg.Date))
+	}
+	b.WriteString(pg.Body)
+
+	return b.String()
+}
+
+// writeHeader writes a header line of a COMMENT or PING block to b.
+// Empty values are omitted unless IncludeDefaults is set.
+func (w *writer) writeHeader(b *strings.Builder, key, value string) {
+	if value == "" && !w.opts.IncludeDefaults {
+		return
+	}
+
+	b.WriteString(key + ": " + value + "\n")
+}
@@ -0,0 +1,385 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestWriteRoundTrip(t *testing.T) {
+	input := `AUTHOR: catatsuy
+TITLE: ポエム
+BASENAME: poem
+STATUS: Publish
+ALLOW COMMENTS: 1
+ALLOW PINGS: 1
+CONVERT BREAKS: 0
+DATE: 04/22/2017 20:41:58
+PRIMARY CATEGORY: ブログ
+CATEGORY: ポエム
+CATEGORY: 技術系
+-----
+BODY:
+<p>body</p>
+<p>bodybody</p>
+<p>bodybodybody</p>
+-----
+EXTENDED BODY:
+<p>extended body</p>
+<p>extended body body</p>
+<p>extended body body body</p>
+-----
+--------
+AUTHOR: catatsuy
+TITLE: 風邪で声を失った話
+BASENAME: 2017/04/09/194939
+STATUS: Publish
+ALLOW COMMENTS: 1
+CONVERT BREAKS: 0
+DATE: 04/09/2017 19:49:39
+CATEGORY: 日常
+-----
+BODY:
+<p>bodybodybody</p>
+-----
+EXTENDED BODY:
+<p>extended body body body</p>
+-----
+EXCERPT:
+excerpt
+-----
+KEYWORDS:
+keywords
+-----
+COMMENT:
+AUTHOR: commenter
+EMAIL: commenter@example.com
+IP: 127.0.0.1
+URL: http://www.example.com/
+DATE: 04/09/2017 20:00:00
+comment
+-----
+COMMENT:
+AUTHOR: commenter2
+second comment
+-----
+--------
+`
+
+	mts, err := Parse(bytes.NewBufferString(input))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != input {
+		t.Errorf("Error writing, expected\n%s\ngot\n%s", input, buf.String())
+	}
+}
+
+func TestWriteOmitDefaults(t *testing.T) {
+	m := NewEntry()
+	m.Title = "title"
+	m.Body = "no trailing newline"
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, []*Entry{m})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "TITLE: title\n-----\nBODY:\nno trailing newline\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+}
+
+func TestWriteDate(t *testing.T) {
+	m := NewEntry()
+	m.Date = time.Date(2017, time.April, 9, 19, 49, 39, 0, time.UTC)
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, []*Entry{m})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "DATE: 04/09/2017 19:49:39\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+}
+
+func TestWriteDate12Hour(t *testing.T) {
+	mts, err := ParseString("DATE: 04/09/2017 07:49:39 PM\n-----\n--------\n")
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	// Write always uses the 24-hour layout for DATE
+	expected := "DATE: 04/09/2017 19:49:39\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+}
+
+func TestWriteIncludeDefaults(t *testing.T) {
+	m := NewEntry()
+	m.Title = "title"
+
+	buf := &bytes.Buffer{}
+	err := WriteWithOptions(buf, []*Entry{m}, WriteOptions{IncludeDefaults: true})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `AUTHOR: 
+TITLE: title
+BASENAME: 
+UNIQUE URL: 
+CONVERT BREAKS: 
+DATE: 01/01/0001 00:00:00
+PRIMARY CATEGORY: 
+TAGS: 
+IMAGE: 
+-----
+BODY:
+-----
+EXTENDED BODY:
+-----
+EXCERPT:
+-----
+KEYWORDS:
+-----
+--------
+`
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+
+	mts, err := Parse(buf)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if len(mts) != 1 || mts[0].Title != "title" || mts[0].AllowComments != DefaultAllowComments {
+		t.Errorf("Error parsing written entry, got %v", mts)
+	}
+}
+
+func TestWriteNoEntry(t *testing.T) {
+	m := NewEntry()
+	m.NoEntry = true
+
+	buf := &bytes.Buffer{}
+	err := Write(buf, []*Entry{m})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := "NO ENTRY: 1\n-----\n--------\n"
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected %q; got %q", expected, buf.String())
+	}
+}
+
+func TestEntryBytes(t *testing.T) {
+	mts, err := ParseString(sampleExport)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	expected := `AUTHOR: catatsuy
+TITLE: ポエム
+BASENAME: poem
+STATUS: Publish
+ALLOW COMMENTS: 1
+ALLOW PINGS: 1
+CONVERT BREAKS: 0
+DATE: 04/22/2017 20:41:58
+PRIMARY CATEGORY: ブログ
+CATEGORY: ポエム
+CATEGORY: 技術系
+-----
+BODY:
+<p>body</p>
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+`
+	if string(mts[0].Bytes()) != expected {
+		t.Errorf("m.Bytes() got\n%s\nwant\n%s", mts[0].Bytes(), expected)
+	}
+
+	buf := &bytes.Buffer{}
+	Write(buf, mts[:1])
+	if buf.String() != expected+"--------\n" {
+		t.Errorf("Write should add the separator to Bytes, got %q", buf.String())
+	}
+}
+
+func TestWriteCanonicalOrder(t *testing.T) {
+	golden := filepath.Join("testdata", "canonical.txt")
+
+	expected, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mts, err := ParseFile(golden)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	// Rebuild the second entry setting fields in another order to check
+	// that the output does not depend on how the entry was built.
+	m := NewEntry()
+	m.Title = mts[1].Title
+	m.Date = mts[1].Date
+	m.Category = mts[1].Category
+	m.Body = mts[1].Body
+	m.ConvertBreaks = mts[1].ConvertBreaks
+	m.Status = mts[1].Status
+	m.Basename = mts[1].Basename
+	m.Author = mts[1].Author
+	mts[1] = m
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if buf.String() != string(expected) {
+		t.Errorf("Write should match %s, expected\n%s\ngot\n%s", golden, expected, buf.String())
+	}
+}
+
+func TestWriteRoundTripColonInValue(t *testing.T) {
+	input := `TITLE: Re: hello world
+UNIQUE URL: https://example.com/a: b
+FAVORITE: key: value
+-----
+BODY:
+body
+-----
+--------
+`
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	if mts[0].Title != "Re: hello world" || mts[0].UniqueURL != "https://example.com/a: b" {
+		t.Errorf("got %q and %q", mts[0].Title, mts[0].UniqueURL)
+	}
+	if v, _ := mts[0].GetCustomField("FAVORITE"); v != "key: value" {
+		t.Errorf("FAVORITE got %q", v)
+	}
+
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if buf.String() != input {
+		t.Errorf("Error writing, expected\n%s\ngot\n%s", input, buf.String())
+	}
+
+	again, err := ParseString(buf.String())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !again[0].Equal(mts[0]) {
+		t.Errorf("Parse → Write → Parse got %v; want %v", again[0], mts[0])
+	}
+}
+
+func TestWriteDateWithOffset(t *testing.T) {
+	input := `DATE: 04/22/2017 20:41:58 +0900
+-----
+COMMENT:
+DATE: 04/23/2017 08:00:00 +0900
+comment
+-----
+--------
+`
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	// Dates are written in their wall-clock time in the format of Movable
+	// Type by default
+	buf := &bytes.Buffer{}
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	expected := strings.Replace(input, " +0900", "", -1)
+	if buf.String() != expected {
+		t.Errorf("Error writing, expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	err = WriteWithOptions(buf, mts, WriteOptions{DateOffset: true})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if buf.String() != input {
+		t.Errorf("Error writing, expected\n%s\ngot\n%s", input, buf.String())
+	}
+
+	again, err := ParseString(buf.String())
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if !again[0].Date.Equal(mts[0].Date) || !again[0].Comments[0].Date.Equal(mts[0].Comments[0].Date) {
+		t.Errorf("dates got %v and %v; want %v and %v", again[0].Date, again[0].Comments[0].Date, mts[0].Date, mts[0].Comments[0].Date)
+	}
+
+	// Dates parsed in a location are written in its local time
+	input = "DATE: 04/22/2017 20:41:58\n-----\n--------\n"
+	mts, err = ParseString(input, WithLocation(time.FixedZone("JST", 9*60*60)))
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	buf.Reset()
+	err = Write(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+
+	// UTC dates have no offset with DateOffset
+	buf.Reset()
+	err = WriteWithOptions(buf, []*Entry{{Date: time.Date(2017, time.April, 22, 20, 41, 58, 0, time.UTC), AllowComments: DefaultAllowComments, AllowPings: DefaultAllowPings}}, WriteOptions{DateOffset: true})
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	if buf.String() != input {
+		t.Errorf("Error writing, expected %q; got %q", input, buf.String())
+	}
+}
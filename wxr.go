@@ -0,0 +1,220 @@
+package movabletype
+
+import (
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Layout of dates in WXR such as wp:post_date
+const wxrDateFormat = "2006-01-02 15:04:05"
+
+// Separator of Body and ExtendedBody in content:encoded
+const wxrMore = "\n<!--more-->\n"
+
+type wxrRSS struct {
+	XMLName      xml.Name   `xml:"rss"`
+	Version      string     `xml:"version,attr"`
+	XMLNSExcerpt string     `xml:"xmlns:excerpt,attr"`
+	XMLNSContent string     `xml:"xmlns:content,attr"`
+	XMLNSDC      string     `xml:"xmlns:dc,attr"`
+	XMLNSWP      string     `xml:"xmlns:wp,attr"`
+	Channel      wxrChannel `xml:"channel"`
+}
+
+type wxrChannel struct {
+	WXRVersion string        `xml:"wp:wxr_version"`
+	Categories []wxrCategory `xml:"wp:category"`
+	Tags       []wxrTag      `xml:"wp:tag"`
+	Items      []wxrItem     `xml:"item"`
+}
+
+type wxrCategory struct {
+	Nicename string `xml:"wp:category_nicename"`
+	Name     cdata  `xml:"wp:cat_name"`
+}
+
+type wxrTag struct {
+	Slug string `xml:"wp:tag_slug"`
+	Name cdata  `xml:"wp:tag_name"`
+}
+
+type wxrItem struct {
+	Title         string        `xml:"title"`
+	PubDate       string        `xml:"pubDate,omitempty"`
+	Creator       cdata         `xml:"dc:creator"`
+	Content       cdata         `xml:"content:encoded"`
+	Excerpt       cdata         `xml:"excerpt:encoded"`
+	PostDate      string        `xml:"wp:post_date,omitempty"`
+	PostDateGMT   string        `xml:"wp:post_date_gmt,omitempty"`
+	CommentStatus string        `xml:"wp:comment_status"`
+	PingStatus    string        `xml:"wp:ping_status"`
+	PostName      string        `xml:"wp:post_name"`
+	Status        string        `xml:"wp:status"`
+	PostType      string        `xml:"wp:post_type"`
+	Categories    []wxrItemTerm `xml:"category"`
+	Comments      []wxrComment  `xml:"wp:comment"`
+}
+
+type wxrItemTerm struct {
+	Domain   string `xml:"domain,attr"`
+	Nicename string `xml:"nicename,attr"`
+	Name     string `xml:",cdata"`
+}
+
+type wxrComment struct {
+	ID          int    `xml:"wp:comment_id"`
+	Author      cdata  `xml:"wp:comment_author"`
+	AuthorEmail string `xml:"wp:comment_author_email"`
+	AuthorURL   string `xml:"wp:comment_author_url"`
+	AuthorIP    string `xml:"wp:comment_author_IP"`
+	Date        string `xml:"wp:comment_date,omitempty"`
+	DateGMT     string `xml:"wp:comment_date_gmt,omitempty"`
+	Content     cdata  `xml:"wp:comment_content"`
+	Approved    string `xml:"wp:comment_approved"`
+}
+
+type cdata struct {
+	Text string `xml:",cdata"`
+}
+
+// WriteWXR writes entries to w as a WordPress eXtended RSS (WXR) document,
+// which can be imported by the WordPress Importer.
+//
+// ExtendedBody is joined to Body with the <!--more--> tag. CATEGORY and
+// PRIMARY CATEGORY are written as categories and TAGS as post tags.
+func WriteWXR(w io.Writer, entries []*Entry) error {
+	doc := wxrRSS{
+		Version:      "2.0",
+		XMLNSExcerpt: "http://wordpress.org/export/1.2/excerpt/",
+		XMLNSContent: "http://purl.org/rss/1.0/modules/content/",
+		XMLNSDC:      "http://purl.org/dc/elements/1.1/",
+		XMLNSWP:      "http://wordpress.org/export/1.2/",
+		Channel: wxrChannel{
+			WXRVersion: "1.2",
+		},
+	}
+
+	categories := map[string]bool{}
+	tags := map[string]bool{}
+
+	for _, e := range entries {
+		item := newWXRItem(e)
+
+		for _, term := range item.Categories {
+			switch term.Domain {
+			case "category":
+				if !categories[term.Name] {
+					categories[term.Name] = true
+					doc.Channel.Categories = append(doc.Channel.Categories, wxrCategory{Nicename: term.Nicename, Name: cdata{term.Name}})
+				}
+			case "post_tag":
+				if !tags[term.Name] {
+					tags[term.Name] = true
+					doc.Channel.Tags = append(doc.Channel.Tags, wxrTag{Slug: term.Nicename, Name: cdata{term.Name}})
+				}
+			}
+		}
+
+		doc.Channel.Items = append(doc.Channel.Items, item)
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func newWXRItem(e *Entry) wxrItem {
+	content := e.Body
+	if e.ExtendedBody != "" {
+		content = strings.TrimSuffix(content, "\n") + wxrMore + e.ExtendedBody
+	}
+
+	item := wxrItem{
+		Title:         e.Title,
+		Creator:       cdata{e.Author},
+		Content:       cdata{content},
+		Excerpt:       cdata{e.Excerpt},
+		CommentStatus: wxrOpen(e.AllowComments),
+		PingStatus:    wxrOpen(e.AllowPings),
+		PostName:      e.Basename,
+		Status:        wxrStatus(e.Status),
+		PostType:      "post",
+	}
+
+	if !e.Date.IsZero() {
+		item.PubDate = e.Date.Format(time.RFC1123Z)
+		item.PostDate = e.Date.Format(wxrDateFormat)
+		item.PostDateGMT = e.Date.UTC().Format(wxrDateFormat)
+	}
+
+	for _, c := range e.AllCategories() {
+		item.Categories = append(item.Categories, wxrItemTerm{Domain: "category", Nicename: wxrNicename(c), Name: c})
+	}
+	for _, tag := range e.Tags {
+		item.Categories = append(item.Categories, wxrItemTerm{Domain: "post_tag", Nicename: wxrNicename(tag), Name: tag})
+	}
+
+	for i, c := range e.Comments {
+		wc := wxrComment{
+			ID:          i + 1,
+			Author:      cdata{c.Author},
+			AuthorEmail: c.Email,
+			AuthorURL:   c.URL,
+			AuthorIP:    c.IP,
+			Content:     cdata{c.Body},
+			Approved:    "1",
+		}
+		if !c.Date.IsZero() {
+			wc.Date = c.Date.Format(wxrDateFormat)
+			wc.DateGMT = c.Date.UTC().Format(wxrDateFormat)
+		}
+		item.Comments = append(item.Comments, wc)
+	}
+
+	return item
+}
+
+// wxrStatus converts STATUS to wp:status. Review, which needs approval as
+// pending posts of WordPress, is imported as pending. Entries without
+// STATUS or with other statuses which WordPress does not know are imported
+// as drafts.
+func wxrStatus(status Status) string {
+	switch strings.ToLower(string(status)) {
+	case "publish":
+		return "publish"
+	case "future":
+		return "future"
+	case "review":
+		return "pending"
+	default:
+		return "draft"
+	}
+}
+
+// wxrOpen converts ALLOW COMMENTS and ALLOW PINGS to "open" or "closed".
+// Entries without them are open as the default of WordPress.
+func wxrOpen(allow int) string {
+	if allow == 0 || allow == 2 {
+		return "closed"
+	}
+	return "open"
+}
+
+// wxrNicename converts the name of a category or tag to its slug.
+func wxrNicename(name string) string {
+	return strings.ToLower(url.PathEscape(strings.Replace(strings.TrimSpace(name), " ", "-", -1)))
+}
@@ -0,0 +1,130 @@
+package movabletype_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	. "github.com/catatsuy/movabletype"
+)
+
+func TestWriteWXR(t *testing.T) {
+	input := `AUTHOR: catatsuy
+TITLE: 風邪で声を失った話
+BASENAME: 2017/04/09/194939
+STATUS: Publish
+ALLOW COMMENTS: 1
+ALLOW PINGS: 0
+DATE: 04/09/2017 19:49:39
+PRIMARY CATEGORY: 日常
+CATEGORY: 日常
+TAGS: go,"movable type"
+-----
+BODY:
+<p>body</p>
+-----
+EXTENDED BODY:
+<p>extended body</p>
+-----
+EXCERPT:
+excerpt
+-----
+COMMENT:
+AUTHOR: commenter
+EMAIL: commenter@example.com
+DATE: 04/09/2017 20:00:00
+comment
+-----
+--------
+`
+
+	mts, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+
+	buf := &bytes.Buffer{}
+	err = WriteWXR(buf, mts)
+	if err != nil {
+		t.Fatalf("got error %q", err)
+	}
+	output := buf.String()
+
+	d := xml.NewDecoder(strings.NewReader(output))
+	for {
+		_, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("WXR should be well-formed, got error %q\n%s", err, output)
+		}
+	}
+
+	expected := []string{
+		`<?xml version="1.0" encoding="UTF-8"?>`,
+		`xmlns:wp="http://wordpress.org/export/1.2/"`,
+		`<wp:category_nicename>%e6%97%a5%e5%b8%b8</wp:category_nicename>`,
+		`<wp:tag_name><![CDATA[movable type]]></wp:tag_name>`,
+		`<title>風邪で声を失った話</title>`,
+		`<pubDate>Sun, 09 Apr 2017 19:49:39 +0000</pubDate>`,
+		`<dc:creator><![CDATA[catatsuy]]></dc:creator>`,
+		"<content:encoded><![CDATA[<p>body</p>\n<!--more-->\n<p>extended body</p>\n]]></content:encoded>",
+		"<excerpt:encoded><![CDATA[excerpt\n]]></excerpt:encoded>",
+		`<wp:post_date>2017-04-09 19:49:39</wp:post_date>`,
+		`<wp:comment_status>open</wp:comment_status>`,
+		`<wp:ping_status>closed</wp:ping_status>`,
+		`<wp:post_name>2017/04/09/194939</wp:post_name>`,
+		`<wp:status>publish</wp:status>`,
+		`<category domain="category" nicename="%e6%97%a5%e5%b8%b8"><![CDATA[日常]]></category>`,
+		`<category domain="post_tag" nicename="movable-type"><![CDATA[movable type]]></category>`,
+		`<wp:comment_author><![CDATA[commenter]]></wp:comment_author>`,
+		`<wp:comment_author_email>commenter@example.com</wp:comment_author_email>`,
+		`<wp:comment_date>2017-04-09 20:00:00</wp:comment_date>`,
+		"<wp:comment_content><![CDATA[comment\n]]></wp:comment_content>",
+	}
+
+	for _, s := range expected {
+		if !strings.Contains(output, s) {
+			t.Errorf("WXR should contain %q, got\n%s", s, output)
+		}
+	}
+
+	if strings.Count(output, "<category domain=\"category\"") != 1 {
+		t.Errorf("PRIMARY CATEGORY and CATEGORY should not be duplicated, got\n%s", output)
+	}
+}
+
+func TestWriteWXRStatus(t *testing.T) {
+	var featuretests = []struct {
+		status   Status
+		expected string
+	}{
+		{"Publish", "publish"},
+		{"Draft", "draft"},
+		{"Future", "future"},
+		{"Review", "pending"},
+		{"review", "pending"},
+		{"Unknown", "draft"},
+		{"", "draft"},
+	}
+
+	for _, ft := range featuretests {
+		m := NewEntry()
+		m.Title = "title"
+		m.Status = ft.status
+
+		buf := &bytes.Buffer{}
+		err := WriteWXR(buf, []*Entry{m})
+		if err != nil {
+			t.Fatalf("got error %q", err)
+		}
+
+		expected := "<wp:status>" + ft.expected + "</wp:status>"
+		if !strings.Contains(buf.String(), expected) {
+			t.Errorf("STATUS %q should be written as %q, got\n%s", ft.status, expected, buf.String())
+		}
+	}
+}